@@ -0,0 +1,330 @@
+// Package cassette records Akamai API requests and responses to files and replays them, so that code built on the
+// service clients can be tested offline with realistic fixtures.
+//
+// To record, send requests through a Recorder and save the cassette afterwards:
+//
+//	rec := cassette.NewRecorder(nil)
+//	ctx := akamai.WithHTTPClient(context.Background(), rec.Client())
+//	maps, err := client.GetMapsWithContext(ctx)
+//	err = rec.Save("testdata/maps.json")
+//
+// To replay, load the cassette into a Replayer:
+//
+//	rep, err := cassette.NewReplayer("testdata/maps.json")
+//	ctx := akamai.WithHTTPClient(context.Background(), rep.Client())
+//
+// Authorization headers and secret JSON fields are scrubbed before interactions are stored, and the API host is not
+// recorded, so cassettes can be committed.
+package cassette
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/corbaltcode/go-akamai"
+)
+
+// A Cassette is a sequence of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// An Interaction is a request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"` // including any query string
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Load reads a cassette from the named file.
+func Load(name string) (*Cassette, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to the named file.
+func (c *Cassette) Save(name string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, append(b, '\n'), 0o644)
+}
+
+// A Recorder is an http.RoundTripper that sends requests with another transport and records each interaction. It is
+// safe for concurrent use.
+type Recorder struct {
+	transport http.RoundTripper
+
+	// RedactedFields are parts of JSON object keys whose values are scrubbed from recorded bodies, in addition to
+	// akamai.DefaultRedactedFields. See akamai.RedactJSON.
+	RedactedFields []string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder that sends requests with transport, or http.DefaultTransport if transport is nil.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport}
+}
+
+// Client returns an HTTP client that uses the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip sends the request and records the interaction. A gzip-encoded response body is decompressed, both in the
+// cassette and in the response returned, so that it can be scrubbed and replayed.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if respBody, err = decodeBody(resp, respBody); err != nil {
+		return nil, err
+	}
+
+	fields := redactedFields(r.RedactedFields)
+	i := Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.RequestURI(),
+			Header: akamai.RedactHeaders(req.Header),
+			Body:   string(akamai.RedactJSON(reqBody, fields)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     akamai.RedactHeaders(resp.Header),
+			Body:       string(akamai.RedactJSON(respBody, fields)),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the interactions recorded so far to the named file.
+func (r *Recorder) Save(name string) error {
+	return r.Cassette().Save(name)
+}
+
+// A Matcher reports whether a request matches a recorded request. body is the body of req, which has already been
+// read and scrubbed in the same way as recorded bodies.
+type Matcher func(req *http.Request, body []byte, recorded Request) bool
+
+// MatchMethodAndPath matches requests with the same method and path, including the query string.
+func MatchMethodAndPath(req *http.Request, body []byte, recorded Request) bool {
+	return req.Method == recorded.Method && req.URL.RequestURI() == recorded.Path
+}
+
+// MatchMethodPathAndBody matches requests with the same method, path and body. JSON bodies are compared by value,
+// ignoring formatting and key order.
+func MatchMethodPathAndBody(req *http.Request, body []byte, recorded Request) bool {
+	return MatchMethodAndPath(req, body, recorded) && equalBodies(body, []byte(recorded.Body))
+}
+
+// A Replayer is an http.RoundTripper that answers requests from a cassette instead of sending them. Each recorded
+// interaction is used at most once, in order, unless AllowReuse is set. It is safe for concurrent use.
+type Replayer struct {
+	// Match decides whether a request matches a recorded request. If nil, MatchMethodPathAndBody is used.
+	Match Matcher
+
+	// AllowReuse allows an interaction to answer more than one request. When set, the first matching interaction is
+	// always used.
+	AllowReuse bool
+
+	// RedactedFields are parts of JSON object keys scrubbed from request bodies before matching, in addition to
+	// akamai.DefaultRedactedFields. They should be the same as those used when recording.
+	RedactedFields []string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayer returns a Replayer that answers requests from the cassette in the named file.
+func NewReplayer(name string) (*Replayer, error) {
+	c, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayerFromCassette(c), nil
+}
+
+// NewReplayerFromCassette returns a Replayer that answers requests from c.
+func NewReplayerFromCassette(c *Cassette) *Replayer {
+	return &Replayer{cassette: c, used: make([]bool, len(c.Interactions))}
+}
+
+// Client returns an HTTP client that uses the replayer as its transport.
+func (r *Replayer) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip returns the recorded response of the first unused interaction matching req. It returns an error if no
+// interaction matches.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	body = akamai.RedactJSON(body, redactedFields(r.RedactedFields))
+
+	match := r.Match
+	if match == nil {
+		match = MatchMethodPathAndBody
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if (r.used[i] && !r.AllowReuse) || !match(req, body, interaction.Request) {
+			continue
+		}
+		r.used[i] = true
+
+		resp := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode:    resp.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        resp.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(resp.Body))),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette: no recorded interaction matches %s %s", req.Method, req.URL.RequestURI())
+}
+
+// Unused returns the recorded interactions that have not answered a request, which is useful for checking that a test
+// made every expected call.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func redactedFields(extra []string) []string {
+	return append(append([]string(nil), akamai.DefaultRedactedFields...), extra...)
+}
+
+// decodeBody decompresses body, the body of resp, if it is gzip-encoded, and replaces the body of resp with the result.
+// Content-Encoding and Content-Length are removed from the response headers, as they no longer describe the body.
+func decodeBody(resp *http.Response, body []byte) ([]byte, error) {
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return body, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = int64(len(b))
+	resp.Uncompressed = true
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+// readBody reads and replaces *body so that it can be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+func equalBodies(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
@@ -0,0 +1,101 @@
+package cassette_test
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/cassette"
+	"github.com/corbaltcode/go-akamai/iam"
+)
+
+const secret = "s3cr3t-client-secret"
+
+// newGzipServer returns a server that answers every request with a gzip-encoded new credential, whether or not the
+// client asked for gzip.
+func newGzipServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(`{"credentialId":7,"clientToken":"akab-new","clientSecret":"` + secret + `","status":"ACTIVE"}`))
+		zw.Close()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRecordReplayGzip(t *testing.T) {
+	srv := newGzipServer(t)
+
+	raw := srv.Client().Transport.(*http.Transport).Clone()
+	raw.DisableCompression = true
+
+	tests := []struct {
+		name      string
+		transport http.RoundTripper
+	}{
+		{"transport decompresses", srv.Client().Transport},
+		{"transport passes gzip through", raw},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &iam.Client{Credentials: akamai.Credentials{
+				Host:         strings.TrimPrefix(srv.URL, "https://"),
+				ClientToken:  "akab-client",
+				ClientSecret: "secret",
+				AccessToken:  "akab-access",
+			}}
+
+			rec := cassette.NewRecorder(tt.transport)
+			ctx := akamai.WithHTTPClient(context.Background(), rec.Client())
+
+			recorded, err := client.CreateCredential(ctx, iam.Self)
+			if err != nil {
+				t.Fatalf("recording: %v", err)
+			}
+			if recorded.ClientSecret != secret {
+				t.Errorf("recorded call returned secret %q, want %q", recorded.ClientSecret, secret)
+			}
+
+			name := filepath.Join(t.TempDir(), "cassette.json")
+			if err := rec.Save(name); err != nil {
+				t.Fatal(err)
+			}
+			b, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(b), secret) {
+				t.Errorf("cassette contains the client secret:\n%s", b)
+			}
+			if strings.Contains(string(b), "Content-Encoding") {
+				t.Errorf("cassette contains Content-Encoding:\n%s", b)
+			}
+
+			rep, err := cassette.NewReplayer(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx = akamai.WithHTTPClient(context.Background(), rep.Client())
+
+			replayed, err := client.CreateCredential(ctx, iam.Self)
+			if err != nil {
+				t.Fatalf("replaying: %v", err)
+			}
+			if replayed.CredentialID != 7 || replayed.Status != iam.CredentialActive {
+				t.Errorf("replayed credential %d %s, want 7 %s", replayed.CredentialID, replayed.Status, iam.CredentialActive)
+			}
+			if replayed.ClientSecret != akamai.Redacted {
+				t.Errorf("replayed secret %q, want %q", replayed.ClientSecret, akamai.Redacted)
+			}
+		})
+	}
+}
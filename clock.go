@@ -0,0 +1,107 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MaxClockSkew is the largest difference between the local clock and Akamai's clock that is not reported as the
+// likely cause of an authentication failure. EdgeGrid rejects requests whose timestamp is too far from the server's
+// time.
+var MaxClockSkew = 30 * time.Second
+
+// ClockSkewError is returned when Akamai rejects a request as unauthorized and the Date header of the response shows
+// that the local clock is more than MaxClockSkew away from Akamai's.
+type ClockSkewError struct {
+	// Skew is Akamai's time minus local time, as measured from the response.
+	Skew time.Duration
+
+	// Err is the error that would have been returned otherwise, containing the response body.
+	Err error
+}
+
+func (e *ClockSkewError) Error() string {
+	direction, skew := "ahead of", e.Skew
+	if skew < 0 {
+		direction, skew = "behind", -skew
+	}
+	return fmt.Sprintf("request rejected while Akamai's clock is %v %s the local clock: %v", skew.Round(time.Second), direction, e.Err)
+}
+
+func (e *ClockSkewError) Unwrap() error {
+	return e.Err
+}
+
+// A Clock measures the offset between the local clock and Akamai's clock from the Date headers of API responses. When
+// Compensate is set, the measured offset is applied to the timestamps of subsequent signed requests, and a request
+// rejected because of clock skew is retried once.
+//
+// A Clock is safe for concurrent use and should be shared by all requests made with the same context.
+type Clock struct {
+	Compensate bool
+
+	mu       sync.Mutex
+	offset   time.Duration
+	measured bool
+}
+
+// Now returns the local time adjusted by the measured offset if Compensate is set, or the local time otherwise.
+func (c *Clock) Now() time.Time {
+	if c == nil || !c.Compensate {
+		return time.Now()
+	}
+	return time.Now().Add(c.Offset())
+}
+
+// Offset returns the most recently measured difference between Akamai's time and local time.
+func (c *Clock) Offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.offset
+}
+
+// Measured reports whether the clock has observed at least one response.
+func (c *Clock) Measured() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.measured
+}
+
+// Observe records the skew implied by a response with the given Date header value, for a request sent at sent and
+// whose response was received at received. It returns the measured skew.
+func (c *Clock) Observe(date, sent, received time.Time) time.Duration {
+	skew := MeasureSkew(date, sent, received)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offset = skew
+	c.measured = true
+	return skew
+}
+
+// MeasureSkew estimates Akamai's time minus local time from a response Date header for a request sent at sent and
+// received at received. Since Date has a resolution of one second, the estimate is accurate to within about half a
+// second plus half the round-trip time.
+func MeasureSkew(date, sent, received time.Time) time.Duration {
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	return date.Add(500 * time.Millisecond).Sub(midpoint)
+}
+
+// WithClock returns a copy of the parent context in which API requests measure clock skew with c and, if
+// c.Compensate is set, sign requests using c.Now.
+func WithClock(ctx context.Context, c *Clock) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, AkamaiClock, c)
+}
+
+// RequestClock returns the clock set in the context by WithClock, or nil if none is set.
+func RequestClock(ctx context.Context) *Clock {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(AkamaiClock).(*Clock)
+	return c
+}
@@ -0,0 +1,405 @@
+// Package cps reads certificate enrollments and deployments from the Akamai Certificate Provisioning System (CPS) and
+// reports on certificate expiry.
+package cps
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/poll"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+const basePath = "/cps/v2/enrollments"
+
+// Media types of the API versions used. CPS requires them in the Accept header.
+const (
+	enrollmentsMediaType = "application/vnd.akamai.cps.enrollments.v11+json"
+	enrollmentMediaType  = "application/vnd.akamai.cps.enrollment.v11+json"
+	deploymentsMediaType = "application/vnd.akamai.cps.deployments.v8+json"
+	changeMediaType      = "application/vnd.akamai.cps.change.v2+json"
+)
+
+// A Client allows access to the Akamai CPS API.
+type Client struct {
+	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
+}
+
+// Enrollment represents a certificate enrollment, which describes the certificate Akamai obtains and renews for a set
+// of hostnames.
+type Enrollment struct {
+	ID int
+
+	// CN and SANs are the common name and subject alternative names requested for the certificate.
+	CN   string
+	SANs []string
+
+	CertificateType  string
+	ValidationType   string
+	RA               string
+	ChangeManagement bool
+	Geography        string
+	SecureNetwork    string
+	SNIOnly          bool
+
+	// PendingChanges are the changes to the enrollment in progress, such as a renewal.
+	PendingChanges []PendingChange
+}
+
+// PendingChange is a change to an enrollment in progress.
+type PendingChange struct {
+	ChangeID   int
+	ChangeType string
+}
+
+func newEnrollmentFromResp(ctx context.Context, r enrollmentResp) (Enrollment, error) {
+	e := Enrollment{
+		ID:               r.ID,
+		CN:               r.CSR.CN,
+		SANs:             r.CSR.SANs,
+		CertificateType:  r.CertificateType,
+		ValidationType:   r.ValidationType,
+		RA:               r.RA,
+		ChangeManagement: r.ChangeManagement,
+		Geography:        r.NetworkConfiguration.Geography,
+		SecureNetwork:    r.NetworkConfiguration.SecureNetwork,
+		SNIOnly:          r.NetworkConfiguration.SNIOnly,
+	}
+
+	if e.ID == 0 {
+		id, err := strconv.Atoi(path.Base(r.Location))
+		if err != nil {
+			akamai.Logger(ctx).DebugContext(ctx, "error parsing enrollment location", "location", r.Location, "error", err)
+
+			return Enrollment{}, err
+		}
+		e.ID = id
+	}
+
+	for _, pc := range r.PendingChanges {
+		id, err := strconv.Atoi(path.Base(pc.Location))
+		if err != nil {
+			akamai.Logger(ctx).DebugContext(ctx, "error parsing change location", "location", pc.Location, "error", err)
+
+			return Enrollment{}, err
+		}
+		e.PendingChanges = append(e.PendingChanges, PendingChange{ChangeID: id, ChangeType: pc.ChangeType})
+	}
+
+	return e, nil
+}
+
+// enrollmentResp is the struct used to unmarshal the JSON response for an enrollment from the API.
+type enrollmentResp struct {
+	ID               int    `json:"id"`
+	Location         string `json:"location"`
+	CertificateType  string `json:"certificateType"`
+	ValidationType   string `json:"validationType"`
+	RA               string `json:"ra"`
+	ChangeManagement bool   `json:"changeManagement"`
+	CSR              struct {
+		CN   string   `json:"cn"`
+		SANs []string `json:"sans"`
+	} `json:"csr"`
+	NetworkConfiguration struct {
+		Geography     string `json:"geography"`
+		SecureNetwork string `json:"secureNetwork"`
+		SNIOnly       bool   `json:"sniOnly"`
+	} `json:"networkConfiguration"`
+	PendingChanges []struct {
+		Location   string `json:"location"`
+		ChangeType string `json:"changeType"`
+	} `json:"pendingChanges"`
+}
+
+// GetEnrollments returns the enrollments in a contract, or in all contracts the client can access if contractID is
+// empty.
+func (c *Client) GetEnrollments(ctx context.Context, contractID string) ([]Enrollment, error) {
+	var resp struct {
+		Enrollments []enrollmentResp `json:"enrollments"`
+	}
+
+	p := basePath
+	if contractID != "" {
+		p += "?" + url.Values{"contractId": {contractID}}.Encode()
+	}
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, p, accept(enrollmentsMediaType), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments := make([]Enrollment, len(resp.Enrollments))
+
+	for i, r := range resp.Enrollments {
+		e, err := newEnrollmentFromResp(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		enrollments[i] = e
+	}
+
+	return enrollments, nil
+}
+
+// GetEnrollment returns an enrollment by ID.
+func (c *Client) GetEnrollment(ctx context.Context, id int) (Enrollment, error) {
+	var resp enrollmentResp
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, enrollmentPath(id), accept(enrollmentMediaType), nil, &resp)
+	if err != nil {
+		return Enrollment{}, err
+	}
+	resp.ID = id
+
+	return newEnrollmentFromResp(ctx, resp)
+}
+
+// Deployments holds the certificates of an enrollment deployed on each network. A network on which nothing is
+// deployed is nil.
+type Deployments struct {
+	Production *Deployment
+	Staging    *Deployment
+}
+
+// Deployment is the set of certificates of an enrollment deployed on a network. An enrollment with multi-stacked
+// certificates, such as both RSA and ECDSA, has more than one.
+type Deployment struct {
+	Certificates []Certificate
+}
+
+// Certificate is a deployed certificate.
+type Certificate struct {
+	KeyAlgorithm       string
+	SignatureAlgorithm string
+
+	// Certificate is the parsed certificate, holding its subject alternative names, validity period and issuer.
+	Certificate *x509.Certificate
+
+	// Chain is the trust chain sent with the certificate, starting with its issuer.
+	Chain []*x509.Certificate
+}
+
+func newDeploymentFromResp(ctx context.Context, r *deploymentResp) (*Deployment, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	d := &Deployment{}
+
+	for _, cr := range append([]certificateResp{r.PrimaryCertificate}, r.MultiStackedCertificates...) {
+		if cr.Certificate == "" {
+			continue
+		}
+
+		certs, err := parseCertificates(cr.Certificate)
+		if err != nil {
+			akamai.Logger(ctx).DebugContext(ctx, "error parsing certificate", "error", err)
+
+			return nil, err
+		}
+		chain, err := parseCertificates(cr.TrustChain)
+		if err != nil {
+			akamai.Logger(ctx).DebugContext(ctx, "error parsing trust chain", "error", err)
+
+			return nil, err
+		}
+
+		d.Certificates = append(d.Certificates, Certificate{
+			KeyAlgorithm:       cr.KeyAlgorithm,
+			SignatureAlgorithm: cr.SignatureAlgorithm,
+			Certificate:        certs[0],
+			Chain:              append(certs[1:], chain...),
+		})
+	}
+
+	return d, nil
+}
+
+// deploymentResp is the struct used to unmarshal the JSON response for a deployment from the API.
+type deploymentResp struct {
+	PrimaryCertificate       certificateResp   `json:"primaryCertificate"`
+	MultiStackedCertificates []certificateResp `json:"multiStackedCertificates"`
+}
+
+type certificateResp struct {
+	Certificate        string `json:"certificate"`
+	TrustChain         string `json:"trustChain"`
+	KeyAlgorithm       string `json:"keyAlgorithm"`
+	SignatureAlgorithm string `json:"signatureAlgorithm"`
+}
+
+// GetDeployments returns the certificates of an enrollment deployed on each network.
+func (c *Client) GetDeployments(ctx context.Context, enrollmentID int) (Deployments, error) {
+	var resp struct {
+		Production *deploymentResp `json:"production"`
+		Staging    *deploymentResp `json:"staging"`
+	}
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, enrollmentPath(enrollmentID)+"/deployments", accept(deploymentsMediaType), nil, &resp)
+	if err != nil {
+		return Deployments{}, err
+	}
+
+	production, err := newDeploymentFromResp(ctx, resp.Production)
+	if err != nil {
+		return Deployments{}, err
+	}
+	staging, err := newDeploymentFromResp(ctx, resp.Staging)
+	if err != nil {
+		return Deployments{}, err
+	}
+
+	return Deployments{Production: production, Staging: staging}, nil
+}
+
+// ChangeStatus is the progress of a change to an enrollment.
+type ChangeStatus struct {
+	// State is the overall state of the change, such as "running", "awaiting-input", "completed" or "error".
+	State string
+
+	// Status is the current step of the change, such as "wait-review-pre-verification-safety-checks".
+	Status      string
+	Description string
+
+	// Error describes why the change failed, if it did.
+	Error *ChangeError
+
+	// AllowedInput lists the input the change is waiting for, such as acknowledgement of warnings.
+	AllowedInput []AllowedInput
+}
+
+// ChangeError describes the failure of a change.
+type ChangeError struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// AllowedInput is input a change is waiting for.
+type AllowedInput struct {
+	Type              string `json:"type"`
+	Info              string `json:"info"`
+	Update            string `json:"update"`
+	RequiredToProceed bool   `json:"requiredToProceed"`
+}
+
+// Change states reported by ChangeStatus.State.
+const (
+	ChangeStateRunning       = "running"
+	ChangeStateAwaitingInput = "awaiting-input"
+	ChangeStateCompleted     = "completed"
+	ChangeStateError         = "error"
+)
+
+// GetChangeStatus returns the progress of a change to an enrollment.
+func (c *Client) GetChangeStatus(ctx context.Context, enrollmentID int, changeID int) (ChangeStatus, error) {
+	var resp struct {
+		AllowedInput []AllowedInput `json:"allowedInput"`
+		StatusInfo   struct {
+			State       string       `json:"state"`
+			Status      string       `json:"status"`
+			Description string       `json:"description"`
+			Error       *ChangeError `json:"error"`
+		} `json:"statusInfo"`
+	}
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, fmt.Sprintf("%s/changes/%d", enrollmentPath(enrollmentID), changeID), accept(changeMediaType), nil, &resp)
+	if err != nil {
+		return ChangeStatus{}, err
+	}
+
+	return ChangeStatus{
+		State:        resp.StatusInfo.State,
+		Status:       resp.StatusInfo.Status,
+		Description:  resp.StatusInfo.Description,
+		Error:        resp.StatusInfo.Error,
+		AllowedInput: resp.AllowedInput,
+	}, nil
+}
+
+// WaitForChange polls a change to an enrollment every interval until it is no longer running, and returns its final
+// status. A change awaiting input is returned without error so the caller can act on AllowedInput. It returns an
+// error if the change fails or the context is done. If interval is zero, the change is polled every 30 seconds.
+func (c *Client) WaitForChange(ctx context.Context, enrollmentID int, changeID int, interval time.Duration) (ChangeStatus, error) {
+	var status ChangeStatus
+
+	err := poll.Until(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		if status, err = c.GetChangeStatus(ctx, enrollmentID, changeID); err != nil {
+			return false, err
+		}
+
+		akamai.Logger(ctx).DebugContext(ctx, "certificate change status", "enrollment", enrollmentID, "change", changeID, "state", status.State, "status", status.Status)
+
+		switch status.State {
+		case ChangeStateError:
+			msg := status.Description
+			if status.Error != nil {
+				msg = status.Error.Description
+			}
+			return false, fmt.Errorf("change %d to enrollment %d failed: %s", changeID, enrollmentID, msg)
+		case ChangeStateRunning:
+			return false, nil
+		}
+		return true, nil
+	})
+
+	return status, err
+}
+
+func enrollmentPath(id int) string {
+	return fmt.Sprintf("%s/%d", basePath, id)
+}
+
+func accept(mediaType string) http.Header {
+	return http.Header{"Accept": {mediaType}}
+}
+
+// parseCertificates parses the PEM-encoded certificates in s.
+func parseCertificates(s string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	rest := []byte(s)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 && s != "" {
+		return nil, errors.New("no PEM certificate found")
+	}
+
+	return certs, nil
+}
@@ -0,0 +1,158 @@
+package cps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+)
+
+// Network is the network a certificate is deployed on.
+type Network string
+
+const (
+	Production Network = "production"
+	Staging    Network = "staging"
+)
+
+// ExpiringCertificate is a deployed certificate that expires within the period of an expiry report.
+type ExpiringCertificate struct {
+	EnrollmentID int
+	CN           string
+	Network      Network
+	Certificate  Certificate
+}
+
+// NotAfter returns when the certificate expires.
+func (e ExpiringCertificate) NotAfter() time.Time {
+	return e.Certificate.Certificate.NotAfter
+}
+
+// GetExpiringCertificates returns the deployed certificates of all enrollments in a contract, or in all contracts if
+// contractID is empty, that expire within the given number of days after now, including those already expired. The
+// result is sorted by expiry, soonest first. Pass the same now to WriteExpiryReport so that the days remaining agree.
+//
+// If the deployments of some enrollments cannot be read, the expiring certificates of the others are still returned,
+// along with a *ReportError describing each enrollment that was skipped.
+func (c *Client) GetExpiringCertificates(ctx context.Context, contractID string, now time.Time, days int) ([]ExpiringCertificate, error) {
+	enrollments, err := c.GetEnrollments(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := now.AddDate(0, 0, days)
+	var expiring []ExpiringCertificate
+	reportErr := &ReportError{Total: len(enrollments)}
+
+	for _, e := range enrollments {
+		deployments, err := c.GetDeployments(ctx, e.ID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			akamai.Logger(ctx).WarnContext(ctx, "skipping enrollment", "enrollment", e.ID, "error", err)
+			reportErr.Errors = append(reportErr.Errors, &EnrollmentError{EnrollmentID: e.ID, CN: e.CN, Err: err})
+			continue
+		}
+
+		for _, d := range []struct {
+			network    Network
+			deployment *Deployment
+		}{
+			{Production, deployments.Production},
+			{Staging, deployments.Staging},
+		} {
+			if d.deployment == nil {
+				continue
+			}
+			for _, cert := range d.deployment.Certificates {
+				if cert.Certificate.NotAfter.Before(deadline) {
+					expiring = append(expiring, ExpiringCertificate{EnrollmentID: e.ID, CN: e.CN, Network: d.network, Certificate: cert})
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(expiring, func(a, b ExpiringCertificate) int {
+		return a.NotAfter().Compare(b.NotAfter())
+	})
+
+	if len(reportErr.Errors) > 0 {
+		return expiring, reportErr
+	}
+
+	return expiring, nil
+}
+
+// EnrollmentError is the failure to read the deployed certificates of one enrollment.
+type EnrollmentError struct {
+	EnrollmentID int
+	CN           string
+	Err          error
+}
+
+func (e *EnrollmentError) Error() string {
+	return fmt.Sprintf("enrollment %d (%s): %v", e.EnrollmentID, e.CN, e.Err)
+}
+
+func (e *EnrollmentError) Unwrap() error {
+	return e.Err
+}
+
+// ReportError is returned by GetExpiringCertificates when the certificates of one or more enrollments could not be
+// checked.
+type ReportError struct {
+	// Total is the number of enrollments checked.
+	Total int
+
+	// Errors contains the failures, in the order the enrollments were listed.
+	Errors []*EnrollmentError
+}
+
+func (e *ReportError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d enrollments could not be checked: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *ReportError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// WriteExpiryReport writes a plain-text report of expiring certificates to w, with a paragraph per certificate giving
+// its expiry, the days remaining relative to now, its issuer and its SANs.
+func WriteExpiryReport(w io.Writer, certs []ExpiringCertificate, now time.Time) error {
+	if _, err := fmt.Fprintf(w, "Certificates expiring: %d\n", len(certs)); err != nil {
+		return err
+	}
+
+	for _, e := range certs {
+		cert := e.Certificate.Certificate
+		days := int(cert.NotAfter.Sub(now).Hours() / 24)
+
+		if _, err := fmt.Fprintf(w, "\n%s (enrollment %d, %s, %s)\n", e.CN, e.EnrollmentID, e.Network, e.Certificate.KeyAlgorithm); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "  Expires: %s (%d days)\n", cert.NotAfter.UTC().Format(time.RFC3339), days); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "  Issuer: %s\n", cert.Issuer); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "  SANs: %s\n", strings.Join(cert.DNSNames, ", ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
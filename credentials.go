@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
 
 	"gopkg.in/ini.v1"
@@ -36,49 +35,37 @@ func LoadCredentialsFromEdgerc(r io.Reader, section string) (Credentials, error)
 }
 
 func LoadCredentialsFromEdgercWithContext(ctx context.Context, r io.Reader, section string) (Credentials, error) {
-	debug := DebugEnabled(ctx)
+	logger := Logger(ctx)
 	f, err := ini.Load(r)
 	if err != nil {
-		if debug {
-			log.Printf("go-akamai: error loading credentials: %v", err)
-		}
+		logger.DebugContext(ctx, "error loading credentials", "error", err)
 		return Credentials{}, err
 	}
 
 	if !f.HasSection(section) {
-		if debug {
-			log.Printf("go-akamai: no section %q in credentials file", section)
-		}
+		logger.DebugContext(ctx, "no section in credentials file", "section", section)
 		return Credentials{}, fmt.Errorf("no section %q", section)
 	}
 	s := f.Section(section)
 
 	clientToken := s.Key("client_token").String()
 	if clientToken == "" {
-		if debug {
-			log.Printf("go-akamai: missing client_token in section %q", section)
-		}
+		logger.DebugContext(ctx, "missing client_token in credentials file", "section", section)
 		return Credentials{}, errors.New("missing client_token")
 	}
 	clientSecret := s.Key("client_secret").String()
 	if clientSecret == "" {
-		if debug {
-			log.Printf("go-akamai: missing client_secret in section %q", section)
-		}
+		logger.DebugContext(ctx, "missing client_secret in credentials file", "section", section)
 		return Credentials{}, errors.New("missing client_secret")
 	}
 	accessToken := s.Key("access_token").String()
 	if accessToken == "" {
-		if debug {
-			log.Printf("go-akamai: missing access_token in section %q", section)
-		}
+		logger.DebugContext(ctx, "missing access_token in credentials file", "section", section)
 		return Credentials{}, errors.New("missing access_token")
 	}
 	host := s.Key("host").String()
 	if host == "" {
-		if debug {
-			log.Printf("go-akamai: missing host in section %q", section)
-		}
+		logger.DebugContext(ctx, "missing host in credentials file", "section", section)
 		return Credentials{}, errors.New("missing host")
 	}
 
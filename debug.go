@@ -1,14 +1,75 @@
 package akamai
 
-import "context"
+import (
+	"context"
+	"log"
+	"log/slog"
+)
 
 // AkamaiContext is a context value type used to enable various features of the go-akamai library.
 type AkamaiContext int
 
-// AkamaiDebug is a context value that can be set to enable debug logging in the go-akamai library.
-const AkamaiDebug AkamaiContext = 0
+const (
+	// AkamaiDebug is a context value that can be set to enable debug logging in the go-akamai library.
+	//
+	// Deprecated: Use WithLogger instead.
+	AkamaiDebug AkamaiContext = 0
+
+	// AkamaiRedactedFields is a context value holding additional JSON fields to redact from debug output. See
+	// WithRedactedFields.
+	AkamaiRedactedFields AkamaiContext = 1
+
+	// AkamaiLogger is a context value holding the *slog.Logger used by the go-akamai library. See WithLogger.
+	AkamaiLogger AkamaiContext = 2
+
+	// AkamaiHooks is a context value holding the Hooks called for each API request. See WithHooks.
+	AkamaiHooks AkamaiContext = 3
+
+	// AkamaiHTTPClient is a context value holding the *http.Client used to send API requests. See WithHTTPClient.
+	AkamaiHTTPClient AkamaiContext = 4
+
+	// AkamaiClock is a context value holding the *Clock used to measure and compensate for clock skew. See WithClock.
+	AkamaiClock AkamaiContext = 5
+
+	// AkamaiMaxResponseSize is a context value holding the maximum size of API response bodies. See
+	// WithMaxResponseSize.
+	AkamaiMaxResponseSize AkamaiContext = 6
+)
+
+// discardLogger is used when no logger is set, so the library is silent by default.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// WithLogger returns a copy of the parent context in which the go-akamai library writes logs to logger.
+//
+// Each API request is logged at slog.LevelInfo with the method, path, status, latency, attempt and a request ID that
+// is shared by every record about the same request. Headers and bodies, with secrets redacted, are logged at
+// slog.LevelDebug. Failures are logged at slog.LevelError.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, AkamaiLogger, logger)
+}
+
+// Logger returns the logger set in the context by WithLogger. If none is set, it returns a logger that discards all
+// records.
+func Logger(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return discardLogger
+	}
+	if logger, ok := ctx.Value(AkamaiLogger).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	if debug, _ := ctx.Value(AkamaiDebug).(bool); debug {
+		return slog.New(slog.NewTextHandler(log.Writer(), &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	return discardLogger
+}
 
 // WithDebugEnabled returns a copy of the parent context with debug enabled.
+//
+// Deprecated: Use WithLogger with a logger that is enabled at slog.LevelDebug. Debug logs enabled with this function
+// are written in text format to the writer of the standard log package.
 func WithDebugEnabled(ctx context.Context) context.Context {
 	if ctx == nil {
 		ctx = context.Background()
@@ -16,12 +77,10 @@ func WithDebugEnabled(ctx context.Context) context.Context {
 	return context.WithValue(ctx, AkamaiDebug, true)
 }
 
-// DebugEnabled returns true if the context has debug enabled.
+// DebugEnabled returns true if the context's logger is enabled at slog.LevelDebug.
 func DebugEnabled(ctx context.Context) bool {
 	if ctx == nil {
 		return false
 	}
-	debugAny := ctx.Value(AkamaiDebug)
-	debug, _ := debugAny.(bool)
-	return debug
+	return Logger(ctx).Enabled(ctx, slog.LevelDebug)
 }
@@ -0,0 +1,8 @@
+// Package akamai provides credentials and request configuration shared by the Akamai API clients in its
+// subpackages.
+//
+// Every API method takes a context. Besides cancelling the request, the context carries the configuration used to
+// make it: the logger (WithLogger), request hooks (WithHooks), the HTTP client (WithHTTPClient), the clock used to
+// measure skew and sign requests (WithClock), the maximum response size (WithMaxResponseSize) and any extra fields to
+// redact from debug output (WithRedactedFields).
+package akamai
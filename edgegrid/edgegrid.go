@@ -1,15 +1,10 @@
 package edgegrid
 
 import (
-	"crypto/hmac"
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/base64"
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
-	"time"
 
 	"github.com/corbaltcode/go-akamai"
 )
@@ -57,63 +52,20 @@ func ParseHeader(header string) (*AuthHeaderInfo, error) {
 	}, nil
 }
 
-// Returns the auth header up through the semicolon before "signature=".
-func generateAuthHeaderPrefix(c akamai.Credentials, timestamp string, nonce string) (string, error) {
-	if nonce == "" {
-		b := make([]byte, 8)
-		if _, err := rand.Read(b); err != nil {
-			return "", err
-		}
-		nonce = fmt.Sprintf("%x", b)
-	}
-
-	header := fmt.Sprintf("EG1-HMAC-SHA256 client_token=%s;access_token=%s;timestamp=%s;nonce=%s;",
-		c.ClientToken, c.AccessToken, timestamp, nonce)
-
-	return header, nil
-}
-
 // Returns the full value that should be set for the "Authorization" header for a request under Akamai's
-// "EdgeGrid" authentication scheme, including the signature.
+// "EdgeGrid" authentication scheme, including the signature. An empty nonce or timestamp is generated.
 func generateAuthHeader(c akamai.Credentials, method, scheme, path string, body []byte, nonce, timestamp string) (string, error) {
-	method = strings.ToUpper(method)
-	if path == "" || path[0] != '/' {
-		path = "/" + path
-	}
+	s := Signer{Credentials: c}
 	if timestamp == "" {
-		timestamp = time.Now().UTC().Format(timeFormat)
-	}
-	prefix, err := generateAuthHeaderPrefix(c, timestamp, nonce)
-	if err != nil {
-		return "", err
+		timestamp = s.timestamp()
 	}
-	contentDigest := ""
-	if method == "POST" && len(body) > 0 {
-		contentHash := sha256.Sum256(body)
-		contentDigest = base64.StdEncoding.EncodeToString(contentHash[:])
+	if nonce == "" {
+		var err error
+		if nonce, err = s.nonce(); err != nil {
+			return "", err
+		}
 	}
-	headers := "" // no signed headers required for FastDNS
-	toSign := strings.Join([]string{
-		method,
-		scheme,
-		c.Host,
-		path,
-		headers,
-		contentDigest,
-		prefix,
-	}, "\t")
-
-	mac := hmac.New(sha256.New, []byte(c.ClientSecret))
-	mac.Write([]byte(timestamp))
-	signingKey := base64.StdEncoding.EncodeToString(mac.Sum(nil))
-
-	mac = hmac.New(sha256.New, []byte(signingKey))
-	mac.Write([]byte(toSign))
-
-	sig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
-	header := fmt.Sprintf("%ssignature=%s", prefix, sig)
-
-	return header, nil
+	return s.sign(method, scheme, c.Host, path, nil, body, timestamp, nonce), nil
 }
 
 // GenerateAuthHeader returns the value that should be set for the "Authorization" header for a request under Akamai's
@@ -124,9 +76,16 @@ func GenerateAuthHeader(c akamai.Credentials, method, scheme, path string, body
 
 // CheckRequest returns true if the AuthHeaderInfo is correct for the given request.
 func CheckRequest(c akamai.Credentials, method, scheme, path string, body []byte, i *AuthHeaderInfo) bool {
+	return CheckRequestWithContext(context.Background(), c, method, scheme, path, body, i)
+}
+
+// CheckRequestWithContext is like CheckRequest, but logs an error computing the expected header to the logger in the
+// context.
+func CheckRequestWithContext(ctx context.Context, c akamai.Credentials, method, scheme, path string, body []byte, i *AuthHeaderInfo) bool {
 	h, err := generateAuthHeader(c, method, scheme, path, body, i.Nonce, i.Timestamp)
 	if err != nil {
-		log.Printf("Unexpected error while checking request: %s", err)
+		akamai.Logger(ctx).ErrorContext(ctx, "error checking request", "error", err)
+		return false
 	}
 	return h == i.FullHeader
 }
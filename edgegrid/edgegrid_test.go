@@ -0,0 +1,168 @@
+package edgegrid
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+)
+
+// The parameters under which the reference signatures were computed.
+var (
+	referenceCredentials = akamai.Credentials{
+		ClientSecret: "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx=",
+		AccessToken:  "akab-access-token-xxx-xxxxxxxxxxxxxxxx",
+		ClientToken:  "akab-client-token-xxx-xxxxxxxxxxxxxxxx",
+		Host:         "akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net",
+	}
+	referenceTimestamp     = time.Date(2014, time.March, 21, 19, 34, 21, 0, time.UTC)
+	referenceNonce         = "nonce-xx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+	referenceHeadersToSign = []string{"X-Test1", "X-Test2", "X-Test3"}
+	referenceMaxBody       = 2048
+)
+
+// referenceAuthPrefix is the part of every expected header before the signature.
+const referenceAuthPrefix = "EG1-HMAC-SHA256 client_token=akab-client-token-xxx-xxxxxxxxxxxxxxxx;" +
+	"access_token=akab-access-token-xxx-xxxxxxxxxxxxxxxx;timestamp=20140321T19:34:21+0000;" +
+	"nonce=nonce-xx-xxxx-xxxx-xxxx-xxxxxxxxxxxx;"
+
+// TestSignerReferenceVectors checks the Signer against the signing test cases published with Akamai's reference
+// EdgeGrid client libraries.
+func TestSignerReferenceVectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		headers  http.Header
+		body     string
+		expected string
+	}{
+		{
+			name:     "simple GET",
+			method:   "GET",
+			path:     "/",
+			expected: referenceAuthPrefix + "signature=tL+y4hxyHxgWVD30X3pWnGKHcPzmrIF+LThiAOhMxYU=",
+		},
+		{
+			name:     "GET with querystring",
+			method:   "GET",
+			path:     "/testapi/v1/t1?p1=1&p2=2",
+			expected: referenceAuthPrefix + "signature=hKDH1UlnQySSHjvIcZpDMbQHihTQ0XyVAKZaApabdeA=",
+		},
+		{
+			name:     "POST inside limit",
+			method:   "POST",
+			path:     "/testapi/v1/t3",
+			body:     "datadatadatadatadatadatadatadata",
+			expected: referenceAuthPrefix + "signature=hXm4iCxtpN22m4cbZb4lVLW5rhX8Ca82vCFqXzSTPe4=",
+		},
+		{
+			name:     "POST too large",
+			method:   "POST",
+			path:     "/testapi/v1/t3",
+			body:     strings.Repeat("d", 2049),
+			expected: referenceAuthPrefix + "signature=6Q6PiTipLae6n4GsSIDTCJ54bEbHUBp+4MUXrbQCBoY=",
+		},
+		{
+			name:     "POST length equals max_body",
+			method:   "POST",
+			path:     "/testapi/v1/t3",
+			body:     strings.Repeat("d", 2048),
+			expected: referenceAuthPrefix + "signature=6Q6PiTipLae6n4GsSIDTCJ54bEbHUBp+4MUXrbQCBoY=",
+		},
+		{
+			name:     "POST empty body",
+			method:   "POST",
+			path:     "/testapi/v1/t6",
+			expected: referenceAuthPrefix + "signature=1gEDxeQGD5GovIkJJGcBaKnZ+VaPtrc4qBUHixjsPCQ=",
+		},
+		{
+			name:   "Simple header signing with GET",
+			method: "GET",
+			path:   "/testapi/v1/t4",
+			headers: http.Header{
+				"X-Test1": {"test-simple-header"},
+			},
+			expected: referenceAuthPrefix + "signature=8F9AybcRw+PLxnvT+H0JRkjROrrUgsxJTnRXMzqvcwY=",
+		},
+		{
+			name:   "Simple header signing with GET. Space in Path",
+			method: "GET",
+			path:   "/testapi/v1/t 4",
+			headers: http.Header{
+				"X-Test1": {"test-simple-header"},
+			},
+			expected: referenceAuthPrefix + "signature=nks5K9Q96uegodgNmzAhLQMgAKaP48dAcKTX+T7Xu8k=",
+		},
+		{
+			name:   "Header containing spaces",
+			method: "GET",
+			path:   "/testapi/v1/t4",
+			headers: http.Header{
+				"X-Test1": {"\"     test-header-with-spaces     \""},
+			},
+			expected: referenceAuthPrefix + "signature=ucq2AbjCNtobHfCTuS38fdkl5UDdWHZhQX46fYR8CqI=",
+		},
+		{
+			name:   "Header with leading and interior spaces",
+			method: "GET",
+			path:   "/testapi/v1/t4",
+			headers: http.Header{
+				"X-Test1": {"     first-thing      second-thing"},
+			},
+			expected: referenceAuthPrefix + "signature=WtnneL539UadAAOJwnsXvPqT4Kt6z7HMgBEwAFpt3+c=",
+		},
+		{
+			name:   "Headers out of order",
+			method: "GET",
+			path:   "/testapi/v1/t4",
+			headers: http.Header{
+				"X-Test2": {"t2"},
+				"X-Test1": {"t1"},
+				"X-Test3": {"t3"},
+			},
+			expected: referenceAuthPrefix + "signature=Wus73Nx8jOYM+kkBFF2q8D1EATRIMr0WLWwpLBgkBqY=",
+		},
+		{
+			name:   "Extra header",
+			method: "GET",
+			path:   "/testapi/v1/t5",
+			headers: http.Header{
+				"X-Test2": {"t2"},
+				"X-Test1": {"t1"},
+				"X-Test3": {"t3"},
+				"X-Extra": {"this won't be included"},
+			},
+			expected: referenceAuthPrefix + "signature=Knd/jc0A5Ghhizjayr0AUUvl2MZjBpS3FDSzvtq4Ixc=",
+		},
+		{
+			name:     "PUT test",
+			method:   "PUT",
+			path:     "/testapi/v1/t6",
+			body:     "PPPPPPPPPPPPPPPPPPPPPPPPPPPPPPP",
+			expected: referenceAuthPrefix + "signature=GNBWEYSEWOLtu+7dD52da2C39aX/Jchpon3K/AmBqBU=",
+		},
+	}
+
+	s := Signer{
+		Credentials:   referenceCredentials,
+		HeadersToSign: referenceHeadersToSign,
+		MaxBody:       referenceMaxBody,
+		Clock:         func() time.Time { return referenceTimestamp },
+		Nonce:         func() (string, error) { return referenceNonce, nil },
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Sign(tt.method, "https", tt.path, tt.headers, []byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
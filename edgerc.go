@@ -0,0 +1,177 @@
+package akamai
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/ini.v1"
+)
+
+// ErrInsecurePermissions is returned by CheckEdgercFilePermissions when an .edgerc file is world-readable.
+var ErrInsecurePermissions = errors.New("credentials file is world-readable")
+
+var hostPattern = regexp.MustCompile(`^akab-[a-z0-9-]+\.luna\.akamaiapis\.net$`)
+
+// EdgercSection describes a section of an .edgerc file without revealing its secrets.
+type EdgercSection struct {
+	Name string
+	Host string
+
+	// ClientTokenFingerprint and AccessTokenFingerprint identify the tokens in the section without revealing them.
+	// See Fingerprint.
+	ClientTokenFingerprint string
+	AccessTokenFingerprint string
+
+	HasClientSecret bool
+
+	// Err describes why the section cannot be used, or is nil if the section is valid.
+	Err error
+}
+
+// Fingerprint returns a short, stable identifier for a token or secret that is safe to display. It is the first 16
+// hex digits of the SHA-256 hash of s, or the empty string if s is empty.
+func Fingerprint(s string) string {
+	if s == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ValidateHost returns an error if host is not an EdgeGrid API host of the form akab-*.luna.akamaiapis.net.
+func ValidateHost(host string) error {
+	if !hostPattern.MatchString(host) {
+		return fmt.Errorf("host %q is not of the form akab-*.luna.akamaiapis.net", host)
+	}
+	return nil
+}
+
+// ListEdgercSections returns a description of every section in an .edgerc file. Sections with missing fields or an
+// invalid host are included with Err set.
+func ListEdgercSections(r io.Reader) ([]EdgercSection, error) {
+	f, err := ini.Load(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var sections []EdgercSection
+	for _, s := range f.Sections() {
+		if s.Name() == ini.DefaultSection && len(s.Keys()) == 0 {
+			continue
+		}
+
+		c := Credentials{
+			ClientToken:  s.Key("client_token").String(),
+			ClientSecret: s.Key("client_secret").String(),
+			AccessToken:  s.Key("access_token").String(),
+			Host:         s.Key("host").String(),
+		}
+		err := c.validate()
+		if err == nil {
+			err = ValidateHost(c.Host)
+		}
+
+		sections = append(sections, EdgercSection{
+			Name:                   s.Name(),
+			Host:                   c.Host,
+			ClientTokenFingerprint: Fingerprint(c.ClientToken),
+			AccessTokenFingerprint: Fingerprint(c.AccessToken),
+			HasClientSecret:        c.ClientSecret != "",
+			Err:                    err,
+		})
+	}
+
+	return sections, nil
+}
+
+// ListEdgercFileSections returns a description of every section in the named .edgerc file.
+func ListEdgercFileSections(name string) ([]EdgercSection, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ListEdgercSections(f)
+}
+
+// CheckEdgercFilePermissions returns an error wrapping ErrInsecurePermissions if the named file can be read by any
+// user on the system.
+func CheckEdgercFilePermissions(name string) error {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	if mode := fi.Mode().Perm(); mode&0o004 != 0 {
+		return fmt.Errorf("%s has mode %v: %w", name, mode, ErrInsecurePermissions)
+	}
+	return nil
+}
+
+// WriteEdgercSection adds or replaces a section of the named .edgerc file with the given credentials, creating the
+// file if it does not exist. Other sections and comments are preserved. The credentials are validated, including the
+// host, before anything is written.
+//
+// The file is replaced atomically and written with mode 0600.
+func WriteEdgercSection(name string, section string, c Credentials) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if err := ValidateHost(c.Host); err != nil {
+		return err
+	}
+
+	f := ini.Empty()
+	b, err := os.ReadFile(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		if f, err = ini.Load(b); err != nil {
+			return err
+		}
+	}
+
+	s := f.Section(section)
+	s.Key("client_secret").SetValue(c.ClientSecret)
+	s.Key("host").SetValue(c.Host)
+	s.Key("access_token").SetValue(c.AccessToken)
+	s.Key("client_token").SetValue(c.ClientToken)
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(name, buf.Bytes(), 0o600)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory as name and renames it over name.
+func writeFileAtomic(name string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,114 @@
+package edgeworkers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// manifestName and mainName are the files every code bundle must contain.
+const (
+	manifestName = "bundle.json"
+	mainName     = "main.js"
+)
+
+// Manifest is the content of a code bundle's bundle.json.
+type Manifest struct {
+	// Version is the version of the EdgeWorker the bundle is uploaded as. It must be unique for the EdgeWorker.
+	Version     string `json:"edgeworker-version"`
+	Description string `json:"description,omitempty"`
+}
+
+// BuildBundle returns a gzipped tar code bundle of the files in dir, ready for Validate or Upload. If manifest is nil,
+// dir must contain a bundle.json; otherwise bundle.json is written from manifest, replacing any in dir. dir must
+// contain a main.js.
+//
+// Files are added in lexical order with fixed modification times and permissions, so the same files always produce
+// the same bundle. Symbolic links and other non-regular files are skipped.
+func BuildBundle(dir string, manifest *Manifest) ([]byte, error) {
+	fsys := os.DirFS(dir)
+
+	if _, err := fs.Stat(fsys, mainName); err != nil {
+		return nil, fmt.Errorf("bundle has no %s: %w", mainName, err)
+	}
+
+	var manifestData []byte
+	if manifest != nil {
+		if manifest.Version == "" {
+			return nil, errors.New("manifest has no version")
+		}
+		var err error
+		if manifestData, err = json.MarshalIndent(manifest, "", "  "); err != nil {
+			return nil, err
+		}
+	} else if _, err := fs.Stat(fsys, manifestName); err != nil {
+		return nil, fmt.Errorf("bundle has no %s: %w", manifestName, err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+
+	if manifestData != nil {
+		if err := addFile(tw, manifestName, bytes.NewReader(manifestData), int64(len(manifestData))); err != nil {
+			return nil, err
+		}
+	}
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || (path == manifestName && manifestData != nil) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		f, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		return addFile(tw, path, f, info.Size())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// addFile writes a regular file to tw.
+func addFile(tw *tar.Writer, name string, r io.Reader, size int64) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0o644,
+		ModTime:  time.Unix(0, 0),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(tw, r)
+	return err
+}
@@ -0,0 +1,279 @@
+// Package edgeworkers manages EdgeWorkers code bundles and their activation with the Akamai EdgeWorkers API.
+package edgeworkers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/poll"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+const basePath = "/edgeworkers/v1"
+
+// A Client allows access to the Akamai EdgeWorkers API.
+type Client struct {
+	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
+}
+
+// EdgeWorker represents an EdgeWorker ID, under which versions of a code bundle are uploaded and activated.
+type EdgeWorker struct {
+	ID               int       `json:"edgeWorkerId"`
+	Name             string    `json:"name"`
+	AccountID        string    `json:"accountId"`
+	GroupID          int64     `json:"groupId"`
+	ResourceTierID   int       `json:"resourceTierId"`
+	CreatedBy        string    `json:"createdBy"`
+	CreatedTime      time.Time `json:"createdTime"`
+	LastModifiedBy   string    `json:"lastModifiedBy"`
+	LastModifiedTime time.Time `json:"lastModifiedTime"`
+}
+
+// Version represents an uploaded code bundle of an EdgeWorker.
+type Version struct {
+	EdgeWorkerID   int       `json:"edgeWorkerId"`
+	Version        string    `json:"version"`
+	AccountID      string    `json:"accountId"`
+	Checksum       string    `json:"checksum"`
+	SequenceNumber int       `json:"sequenceNumber"`
+	CreatedBy      string    `json:"createdBy"`
+	CreatedTime    time.Time `json:"createdTime"`
+}
+
+// GetEdgeWorkers returns the EdgeWorker IDs the client can access.
+func (c *Client) GetEdgeWorkers(ctx context.Context) ([]EdgeWorker, error) {
+	var resp struct {
+		EdgeWorkers []EdgeWorker `json:"edgeWorkerIds"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePath+"/ids", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.EdgeWorkers, nil
+}
+
+// GetEdgeWorker returns an EdgeWorker ID.
+func (c *Client) GetEdgeWorker(ctx context.Context, id int) (EdgeWorker, error) {
+	var ew EdgeWorker
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, idPath(id), nil, &ew)
+	if err != nil {
+		return EdgeWorker{}, err
+	}
+
+	return ew, nil
+}
+
+// GetVersions returns the versions uploaded for an EdgeWorker.
+func (c *Client) GetVersions(ctx context.Context, id int) ([]Version, error) {
+	var resp struct {
+		Versions []Version `json:"versions"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, idPath(id)+"/versions", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Versions, nil
+}
+
+// GetVersion returns a version of an EdgeWorker.
+func (c *Client) GetVersion(ctx context.Context, id int, version string) (Version, error) {
+	var v Version
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, idPath(id)+"/versions/"+url.PathEscape(version), nil, &v)
+	if err != nil {
+		return Version{}, err
+	}
+
+	return v, nil
+}
+
+// ValidationResult holds the problems found in a code bundle. A bundle with errors cannot be uploaded.
+type ValidationResult struct {
+	Errors   []ValidationIssue `json:"errors"`
+	Warnings []ValidationIssue `json:"warnings"`
+}
+
+// ValidationIssue is a problem found in a code bundle.
+type ValidationIssue struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Validate checks a gzipped tar code bundle, as built by BuildBundle, without uploading it.
+func (c *Client) Validate(ctx context.Context, bundle []byte) (ValidationResult, error) {
+	var result ValidationResult
+
+	err := c.doBundle(ctx, basePath+"/validations", bundle, &result)
+	if err != nil {
+		return ValidationResult{}, err
+	}
+
+	return result, nil
+}
+
+// Upload uploads a gzipped tar code bundle, as built by BuildBundle, as a new version of an EdgeWorker. The version
+// is taken from the bundle's bundle.json.
+func (c *Client) Upload(ctx context.Context, id int, bundle []byte) (Version, error) {
+	var v Version
+
+	err := c.doBundle(ctx, idPath(id)+"/versions", bundle, &v)
+	if err != nil {
+		return Version{}, err
+	}
+
+	return v, nil
+}
+
+// doBundle posts a code bundle to path and decodes the JSON response into out.
+func (c *Client) doBundle(ctx context.Context, path string, bundle []byte, out interface{}) error {
+	header := http.Header{"Content-Type": {"application/gzip"}}
+
+	body, err := request.DoStreamWithContext(ctx, c.provider(), http.MethodPost, path, header, bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		akamai.Logger(ctx).DebugContext(ctx, "error parsing response", "path", path, "error", err)
+
+		return err
+	}
+
+	return nil
+}
+
+// Network is the Akamai network on which an EdgeWorker version is activated.
+type Network string
+
+const (
+	Staging    Network = "STAGING"
+	Production Network = "PRODUCTION"
+)
+
+// ActivationStatus is the progress of an activation.
+type ActivationStatus string
+
+const (
+	ActivationPresubmit  ActivationStatus = "PRESUBMIT"
+	ActivationPending    ActivationStatus = "PENDING"
+	ActivationInProgress ActivationStatus = "IN_PROGRESS"
+	ActivationComplete   ActivationStatus = "COMPLETE"
+	ActivationAborted    ActivationStatus = "ABORTED"
+)
+
+// Activation represents the activation of an EdgeWorker version on a network.
+type Activation struct {
+	ID               int              `json:"activationId"`
+	EdgeWorkerID     int              `json:"edgeWorkerId"`
+	Version          string           `json:"version"`
+	Network          Network          `json:"network"`
+	Status           ActivationStatus `json:"status"`
+	Note             string           `json:"note"`
+	AccountID        string           `json:"accountId"`
+	CreatedBy        string           `json:"createdBy"`
+	CreatedTime      time.Time        `json:"createdTime"`
+	LastModifiedTime time.Time        `json:"lastModifiedTime"`
+}
+
+// Activate starts activating a version of an EdgeWorker on a network and returns the activation. Use
+// WaitForActivation to wait for it to finish.
+func (c *Client) Activate(ctx context.Context, id int, version string, network Network, note string) (Activation, error) {
+	body := struct {
+		Network Network `json:"network"`
+		Version string  `json:"version"`
+		Note    string  `json:"note,omitempty"`
+	}{network, version, note}
+
+	var a Activation
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, idPath(id)+"/activations", body, &a)
+	if err != nil {
+		return Activation{}, err
+	}
+
+	return a, nil
+}
+
+// GetActivations returns the activation history of an EdgeWorker, limited to one version if version is not empty.
+func (c *Client) GetActivations(ctx context.Context, id int, version string) ([]Activation, error) {
+	var resp struct {
+		Activations []Activation `json:"activations"`
+	}
+
+	p := idPath(id) + "/activations"
+	if version != "" {
+		p += "?" + url.Values{"version": {version}}.Encode()
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, p, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Activations, nil
+}
+
+// GetActivation returns an activation of an EdgeWorker.
+func (c *Client) GetActivation(ctx context.Context, id int, activationID int) (Activation, error) {
+	var a Activation
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, fmt.Sprintf("%s/activations/%d", idPath(id), activationID), nil, &a)
+	if err != nil {
+		return Activation{}, err
+	}
+
+	return a, nil
+}
+
+// WaitForActivation polls an activation every interval until it is complete and returns its final state. It returns
+// an error if the activation is aborted or the context is done. If interval is zero, the activation is polled every
+// 30 seconds.
+func (c *Client) WaitForActivation(ctx context.Context, id int, activationID int, interval time.Duration) (Activation, error) {
+	var a Activation
+
+	err := poll.Until(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		if a, err = c.GetActivation(ctx, id, activationID); err != nil {
+			return false, err
+		}
+
+		akamai.Logger(ctx).DebugContext(ctx, "edgeworker activation status", "edgeworker", id, "activation", activationID, "status", a.Status)
+
+		switch a.Status {
+		case ActivationComplete:
+			return true, nil
+		case ActivationAborted:
+			return false, fmt.Errorf("activation %d of edgeworker %d version %s on %s: %s", activationID, id, a.Version, a.Network, a.Status)
+		}
+		return false, nil
+	})
+
+	return a, err
+}
+
+func idPath(id int) string {
+	return fmt.Sprintf("%s/ids/%d", basePath, id)
+}
@@ -0,0 +1,287 @@
+package fastdns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/poll"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+// BulkOperation is the kind of a bulk zone request.
+type BulkOperation string
+
+const (
+	BulkCreate BulkOperation = "create"
+	BulkDelete BulkOperation = "delete"
+)
+
+// BulkRequest identifies a submitted bulk zone request. It may be saved as JSON and later passed to WaitForBulk to
+// resume waiting for the request.
+type BulkRequest struct {
+	Operation      BulkOperation `json:"operation"`
+	RequestID      string        `json:"requestId"`
+	ExpirationDate string        `json:"expirationDate"`
+}
+
+// BulkStatus is the progress of a bulk zone request.
+type BulkStatus struct {
+	RequestID      string `json:"requestId"`
+	ZonesSubmitted int    `json:"zonesSubmitted"`
+	SuccessCount   int    `json:"successCount"`
+	FailureCount   int    `json:"failureCount"`
+	IsComplete     bool   `json:"isComplete"`
+	ExpirationDate string `json:"expirationDate"`
+}
+
+// BulkResult is the outcome of a completed bulk zone request for each zone.
+type BulkResult struct {
+	RequestID string
+	Succeeded []string
+	Failed    []BulkFailedZone
+}
+
+// BulkFailedZone is a zone that a bulk request could not create or delete.
+type BulkFailedZone struct {
+	Zone          string `json:"zone"`
+	FailureReason string `json:"failureReason"`
+}
+
+// bulkResultResp is the struct used to unmarshal the JSON response for the result of either kind of bulk request.
+type bulkResultResp struct {
+	RequestID                string           `json:"requestId"`
+	SuccessfullyCreatedZones []string         `json:"successfullyCreatedZones"`
+	SuccessfullyDeletedZones []string         `json:"successfullyDeletedZones"`
+	FailedZones              []BulkFailedZone `json:"failedZones"`
+}
+
+// SubmitBulkCreate submits a request to create zones in a contract and group, which Akamai processes in the
+// background. groupID may be empty if the contract has a single group. Use WaitForBulk to wait for the result.
+func (c *Client) SubmitBulkCreate(ctx context.Context, zones []ZoneConfig, contractID string, groupID string) (BulkRequest, error) {
+	query := url.Values{"contractId": {contractID}}
+	if groupID != "" {
+		query.Set("gid", groupID)
+	}
+	body := struct {
+		Zones []ZoneConfig `json:"zones"`
+	}{zones}
+
+	return c.submitBulk(ctx, BulkCreate, query, body)
+}
+
+// SubmitBulkDelete submits a request to delete zones, which Akamai processes in the background. Unless
+// bypassSafetyChecks is set, zones that are still delegated to Akamai's name servers are not deleted. Use WaitForBulk
+// to wait for the result.
+func (c *Client) SubmitBulkDelete(ctx context.Context, zones []string, bypassSafetyChecks bool) (BulkRequest, error) {
+	query := url.Values{"bypassSafetyChecks": {strconv.FormatBool(bypassSafetyChecks)}}
+
+	return c.submitBulk(ctx, BulkDelete, query, zoneList{Zones: zones})
+}
+
+func (c *Client) submitBulk(ctx context.Context, op BulkOperation, query url.Values, body interface{}) (BulkRequest, error) {
+	var req BulkRequest
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, bulkPath(op)+"?"+query.Encode(), body, &req)
+	if err != nil {
+		return BulkRequest{}, err
+	}
+	req.Operation = op
+
+	return req, nil
+}
+
+// GetBulkStatus returns the progress of a bulk zone request.
+func (c *Client) GetBulkStatus(ctx context.Context, req BulkRequest) (BulkStatus, error) {
+	var status BulkStatus
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, bulkPath(req.Operation)+"/"+url.PathEscape(req.RequestID), nil, &status)
+	if err != nil {
+		return BulkStatus{}, err
+	}
+
+	return status, nil
+}
+
+// GetBulkResult returns the outcome of a completed bulk zone request.
+func (c *Client) GetBulkResult(ctx context.Context, req BulkRequest) (BulkResult, error) {
+	var resp bulkResultResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, bulkPath(req.Operation)+"/"+url.PathEscape(req.RequestID)+"/result", nil, &resp)
+	if err != nil {
+		return BulkResult{}, err
+	}
+
+	succeeded := resp.SuccessfullyCreatedZones
+	if req.Operation == BulkDelete {
+		succeeded = resp.SuccessfullyDeletedZones
+	}
+
+	return BulkResult{RequestID: resp.RequestID, Succeeded: succeeded, Failed: resp.FailedZones}, nil
+}
+
+// WaitForBulk polls the status of a bulk zone request every interval until it is complete, and returns its result.
+// Failures of individual zones are reported in the result rather than as an error. If interval is zero, the status
+// is polled every 30 seconds.
+func (c *Client) WaitForBulk(ctx context.Context, req BulkRequest, interval time.Duration) (BulkResult, error) {
+	return c.WaitForBulkWithProgress(ctx, req, interval, nil)
+}
+
+// WaitForBulkWithProgress is like WaitForBulk but calls progress, if not nil, with each status it polls, so that
+// callers can report how many zones have been processed so far.
+func (c *Client) WaitForBulkWithProgress(ctx context.Context, req BulkRequest, interval time.Duration, progress func(BulkStatus)) (BulkResult, error) {
+	err := poll.Until(ctx, interval, func(ctx context.Context) (bool, error) {
+		status, err := c.GetBulkStatus(ctx, req)
+		if err != nil {
+			return false, err
+		}
+
+		akamai.Logger(ctx).DebugContext(ctx, "bulk zone request status", "operation", req.Operation, "request", req.RequestID,
+			"submitted", status.ZonesSubmitted, "succeeded", status.SuccessCount, "failed", status.FailureCount)
+		if progress != nil {
+			progress(status)
+		}
+
+		return status.IsComplete, nil
+	})
+	if err != nil {
+		return BulkResult{}, err
+	}
+
+	return c.GetBulkResult(ctx, req)
+}
+
+func bulkPath(op BulkOperation) string {
+	return basePathV2 + "zones/" + string(op) + "-requests"
+}
+
+// DefaultParallelism is the number of zones ForEachZone processes at once when given a parallelism of zero.
+const DefaultParallelism = 4
+
+// ZoneError is the failure of an operation on one zone.
+type ZoneError struct {
+	Zone string
+	Err  error
+}
+
+func (e *ZoneError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Zone, e.Err)
+}
+
+func (e *ZoneError) Unwrap() error {
+	return e.Err
+}
+
+// BulkError is returned by ForEachZone when the operation fails for one or more zones.
+type BulkError struct {
+	// Total is the number of zones the operation was applied to.
+	Total int
+
+	// Errors contains the failures, in the order the zones were given.
+	Errors []*ZoneError
+}
+
+func (e *BulkError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d zones failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ZoneProgress reports that ForEachZoneWithProgress has finished with a zone.
+type ZoneProgress struct {
+	Zone string
+
+	// Err is the error fn returned for the zone, or the context's error if the zone was not started.
+	Err error
+
+	// Done is the number of zones finished so far, including this one, out of Total.
+	Done  int
+	Total int
+}
+
+// ForEachZone calls fn for each of zones, running at most parallelism calls at once, and returns a *BulkError
+// describing every zone for which fn failed. If the context is done, zones not yet started fail with the context's
+// error. If parallelism is zero, DefaultParallelism is used.
+//
+// It applies per-zone operations that have no bulk endpoint, or serves as a fallback when bulk requests are
+// unavailable:
+//
+//	err := fastdns.ForEachZone(ctx, names, 8, func(ctx context.Context, zone string) error {
+//		return client.DeleteZoneTSIGKey(ctx, zone)
+//	})
+func ForEachZone(ctx context.Context, zones []string, parallelism int, fn func(ctx context.Context, zone string) error) error {
+	return ForEachZoneWithProgress(ctx, zones, parallelism, fn, nil)
+}
+
+// ForEachZoneWithProgress is like ForEachZone but calls progress, if not nil, as each zone finishes. Calls to
+// progress are not concurrent, so it need not be safe for concurrent use, but it should return quickly because zones
+// wait for it to finish.
+func ForEachZoneWithProgress(ctx context.Context, zones []string, parallelism int, fn func(ctx context.Context, zone string) error, progress func(ZoneProgress)) error {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	errs := make([]error, len(zones))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	var mu sync.Mutex
+	done := 0
+	finish := func(i int, err error) {
+		errs[i] = err
+		if progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		progress(ZoneProgress{Zone: zones[i], Err: err, Done: done, Total: len(zones)})
+	}
+
+	for i, zone := range zones {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			finish(i, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			finish(i, fn(ctx, zone))
+		}()
+	}
+	wg.Wait()
+
+	bulkErr := &BulkError{Total: len(zones)}
+	for i, err := range errs {
+		if err != nil {
+			bulkErr.Errors = append(bulkErr.Errors, &ZoneError{Zone: zones[i], Err: err})
+		}
+	}
+	if len(bulkErr.Errors) > 0 {
+		return bulkErr
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package fastdns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/poll"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+// RecordSet is the set of records of one type at one name, as managed by the Edge DNS v2 API.
+type RecordSet struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	TTL   int      `json:"ttl"`
+	Rdata []string `json:"rdata"`
+}
+
+// GetRecordSets returns the live record sets of a zone.
+func (c *Client) GetRecordSets(ctx context.Context, zone string) ([]RecordSet, error) {
+	return c.getRecordSets(ctx, zonePath(zone)+"/recordsets?showAll=true")
+}
+
+func (c *Client) getRecordSets(ctx context.Context, path string) ([]RecordSet, error) {
+	var resp struct {
+		RecordSets []RecordSet `json:"recordsets"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, path, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.RecordSets, nil
+}
+
+// A ChangeList stages edits to the record sets of a zone so that they can be reviewed and then applied together. A
+// zone has at most one change list at a time, so the Client methods that act on a change list take the zone's name.
+type ChangeList struct {
+	Zone string `json:"zone"`
+
+	// ZoneVersionID is the version of the zone that the change list was created from.
+	ZoneVersionID    string `json:"zoneVersionId"`
+	ChangeTag        string `json:"changeTag"`
+	LastModifiedDate string `json:"lastModifiedDate"`
+
+	// Stale is set if the zone has changed since the change list was created. A stale change list cannot be
+	// submitted.
+	Stale bool `json:"stale"`
+}
+
+// CreateChangeList creates a change list from the current record sets of a zone.
+func (c *Client) CreateChangeList(ctx context.Context, zone string) (ChangeList, error) {
+	return c.doChangeList(ctx, http.MethodPost, basePathV2+"changelists?"+url.Values{"zone": {zone}}.Encode())
+}
+
+// GetChangeList returns the existing change list of a zone.
+func (c *Client) GetChangeList(ctx context.Context, zone string) (ChangeList, error) {
+	return c.doChangeList(ctx, http.MethodGet, changeListPath(zone))
+}
+
+func (c *Client) doChangeList(ctx context.Context, method string, path string) (ChangeList, error) {
+	var cl ChangeList
+
+	err := request.DoJSONWithContext(ctx, c.provider(), method, path, nil, &cl)
+	if err != nil {
+		return ChangeList{}, err
+	}
+
+	return cl, nil
+}
+
+// GetChangeListRecordSets returns the record sets of a zone as they would be once its change list is submitted.
+func (c *Client) GetChangeListRecordSets(ctx context.Context, zone string) ([]RecordSet, error) {
+	return c.getRecordSets(ctx, changeListPath(zone)+"/recordsets?showAll=true")
+}
+
+// AddRecordSet stages the addition of a record set in the change list of a zone. The zone must not already have a
+// record set of the same name and type.
+func (c *Client) AddRecordSet(ctx context.Context, zone string, rs RecordSet) error {
+	return c.changeRecordSet(ctx, zone, ChangeAdd, rs)
+}
+
+// ModifyRecordSet stages, in the change list of a zone, the replacement of the record set with the name and type of
+// rs.
+func (c *Client) ModifyRecordSet(ctx context.Context, zone string, rs RecordSet) error {
+	return c.changeRecordSet(ctx, zone, ChangeModify, rs)
+}
+
+// DeleteRecordSet stages, in the change list of a zone, the deletion of the record set with the given name and type.
+func (c *Client) DeleteRecordSet(ctx context.Context, zone string, name string, recordType string) error {
+	return c.changeRecordSet(ctx, zone, ChangeDelete, RecordSet{Name: name, Type: recordType})
+}
+
+func (c *Client) changeRecordSet(ctx context.Context, zone string, op ChangeOp, rs RecordSet) error {
+	body := struct {
+		RecordSet
+		Op ChangeOp `json:"op"`
+	}{rs, op}
+
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, changeListPath(zone)+"/recordsets/add-change", body, nil)
+}
+
+// DiffChangeList compares the change list of a zone with the live zone and returns the changes that submitting it
+// would make.
+func (c *Client) DiffChangeList(ctx context.Context, zone string) ([]RecordSetChange, error) {
+	live, err := c.GetRecordSets(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	staged, err := c.GetChangeListRecordSets(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffRecordSets(live, staged), nil
+}
+
+// SubmitChangeList applies the change list of a zone. The change list is consumed; use WaitForChangeList to wait for
+// the new version of the zone to be served.
+func (c *Client) SubmitChangeList(ctx context.Context, zone string) error {
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, changeListPath(zone)+"/submit", nil, nil)
+}
+
+// DiscardChangeList deletes the change list of a zone without applying it.
+func (c *Client) DiscardChangeList(ctx context.Context, zone string) error {
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodDelete, changeListPath(zone), nil, nil)
+}
+
+// WaitForChangeList polls the zone of a submitted change list every interval until a version newer than the one the
+// change list was created from is active, and returns the zone's configuration. It returns an error if activation
+// fails or the context is done. If interval is zero, the zone is polled every 30 seconds.
+func (c *Client) WaitForChangeList(ctx context.Context, cl ChangeList, interval time.Duration) (ZoneConfig, error) {
+	var z ZoneConfig
+
+	err := poll.Until(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		if z, err = c.GetZoneConfig(ctx, cl.Zone); err != nil {
+			return false, err
+		}
+
+		akamai.Logger(ctx).DebugContext(ctx, "zone activation state", "zone", cl.Zone, "version", z.VersionID, "state", z.ActivationState)
+
+		if z.ActivationState == "ERROR" {
+			return false, fmt.Errorf("activation of zone %s version %s failed", cl.Zone, z.VersionID)
+		}
+		return z.VersionID != cl.ZoneVersionID && z.ActivationState == "ACTIVE", nil
+	})
+
+	return z, err
+}
+
+// ChangeOp is the kind of change made to a record set.
+type ChangeOp string
+
+const (
+	ChangeAdd    ChangeOp = "ADD"
+	ChangeModify ChangeOp = "EDIT"
+	ChangeDelete ChangeOp = "DELETE"
+)
+
+// RecordSetChange is a difference between two versions of a zone's record sets.
+type RecordSetChange struct {
+	Op ChangeOp
+
+	// Old is the record set before the change, or nil if it is added.
+	Old *RecordSet
+
+	// New is the record set after the change, or nil if it is deleted.
+	New *RecordSet
+}
+
+// DiffRecordSets returns the changes that turn the record sets from into those of to, sorted by name and type. Record
+// sets are matched by name and type, ignoring case, and modified if their TTL or records differ. The order of records
+// within a set is not significant.
+func DiffRecordSets(from, to []RecordSet) []RecordSetChange {
+	type key struct{ name, typ string }
+	keyOf := func(rs RecordSet) key {
+		return key{strings.ToLower(strings.TrimSuffix(rs.Name, ".")), strings.ToUpper(rs.Type)}
+	}
+
+	old := make(map[key]*RecordSet, len(from))
+	for i := range from {
+		old[keyOf(from[i])] = &from[i]
+	}
+
+	var changes []RecordSetChange
+	seen := make(map[key]bool, len(to))
+	for i := range to {
+		k := keyOf(to[i])
+		seen[k] = true
+		if o, ok := old[k]; !ok {
+			changes = append(changes, RecordSetChange{Op: ChangeAdd, New: &to[i]})
+		} else if !equalRecordSets(*o, to[i]) {
+			changes = append(changes, RecordSetChange{Op: ChangeModify, Old: o, New: &to[i]})
+		}
+	}
+	for i := range from {
+		if !seen[keyOf(from[i])] {
+			changes = append(changes, RecordSetChange{Op: ChangeDelete, Old: &from[i]})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b RecordSetChange) int {
+		ka, kb := keyOf(a.recordSet()), keyOf(b.recordSet())
+		if c := strings.Compare(ka.name, kb.name); c != 0 {
+			return c
+		}
+		return strings.Compare(ka.typ, kb.typ)
+	})
+
+	return changes
+}
+
+func (c RecordSetChange) recordSet() RecordSet {
+	if c.New != nil {
+		return *c.New
+	}
+	return *c.Old
+}
+
+func equalRecordSets(a, b RecordSet) bool {
+	if a.TTL != b.TTL || len(a.Rdata) != len(b.Rdata) {
+		return false
+	}
+	ra, rb := slices.Clone(a.Rdata), slices.Clone(b.Rdata)
+	slices.Sort(ra)
+	slices.Sort(rb)
+	return slices.Equal(ra, rb)
+}
+
+func changeListPath(zone string) string {
+	return basePathV2 + "changelists/" + url.PathEscape(zone)
+}
@@ -1,6 +1,8 @@
 package fastdns
 
 import (
+	"context"
+	"io"
 	"net/http"
 	"sort"
 
@@ -93,13 +95,24 @@ type DNSRecord map[string]interface{}
 
 type Client struct {
 	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
 }
 
 // Returns the current zone info, with each set of records sorted in an arbitrary but
 // consistent order.
 func (c *Client) GetZone(name string) (*ZoneResponse, error) {
 	var zr ZoneResponse
-	err := request.DoJSON(c.Credentials, http.MethodGet, "/config-dns/v1/zones/"+name, nil, &zr)
+	err := request.DoJSON(c.provider(), http.MethodGet, "/config-dns/v1/zones/"+name, nil, &zr)
 	if err != nil {
 		return nil, err
 	}
@@ -109,5 +122,12 @@ func (c *Client) GetZone(name string) (*ZoneResponse, error) {
 
 // Updates the current zone.
 func (c *Client) SetZone(name string, zr *ZoneResponse) error {
-	return request.DoJSON(c.Credentials, http.MethodPost, "/config-dns/v1/zones/"+name, zr, nil)
+	return request.DoJSON(c.provider(), http.MethodPost, "/config-dns/v1/zones/"+name, zr, nil)
+}
+
+// ExportZoneFile returns the zone in master file format. The file is streamed rather than held in memory, so this
+// is suitable for large zones; the caller must close it.
+func (c *Client) ExportZoneFile(ctx context.Context, name string) (io.ReadCloser, error) {
+	header := http.Header{"Accept": {"text/dns"}}
+	return request.DoStreamWithContext(ctx, c.provider(), http.MethodGet, "/config-dns/v2/zones/"+name+"/zone-file", header, nil, 0)
 }
@@ -0,0 +1,98 @@
+package fastdns
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+// TSIGKey is a shared secret that authenticates zone transfers from the masters of a secondary zone. A key is
+// identified by its name, algorithm and secret together; the same key may be used by many zones.
+type TSIGKey struct {
+	Name      string `json:"name"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Secret    string `json:"secret,omitempty"`
+
+	// ZoneCount is the number of zones using the key. It is returned by GetTSIGKeys and ignored in requests.
+	ZoneCount int `json:"zonesCount,omitempty"`
+}
+
+// GetTSIGKeys returns the TSIG keys used by zones that the client can access.
+func (c *Client) GetTSIGKeys(ctx context.Context) ([]TSIGKey, error) {
+	var resp struct {
+		Keys []TSIGKey `json:"keys"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePathV2+"keys", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Keys, nil
+}
+
+// GetZoneTSIGKey returns the TSIG key used by a zone.
+func (c *Client) GetZoneTSIGKey(ctx context.Context, zone string) (TSIGKey, error) {
+	var key TSIGKey
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, zonePath(zone)+"/key", nil, &key)
+	if err != nil {
+		return TSIGKey{}, err
+	}
+
+	return key, nil
+}
+
+// SetZoneTSIGKey sets the TSIG key used by a zone, adding or replacing it.
+func (c *Client) SetZoneTSIGKey(ctx context.Context, zone string, key TSIGKey) error {
+	key.ZoneCount = 0
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodPut, zonePath(zone)+"/key", key, nil)
+}
+
+// DeleteZoneTSIGKey removes the TSIG key from a zone, so that its transfers are no longer authenticated.
+func (c *Client) DeleteZoneTSIGKey(ctx context.Context, zone string) error {
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodDelete, zonePath(zone)+"/key", nil, nil)
+}
+
+// GetTSIGKeyZones returns the names of the zones that use a TSIG key.
+func (c *Client) GetTSIGKeyZones(ctx context.Context, key TSIGKey) ([]string, error) {
+	var resp zoneList
+
+	key.ZoneCount = 0
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, basePathV2+"keys/used-by", key, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Zones, nil
+}
+
+// AssignTSIGKey sets the TSIG key used by each of zones in a single request.
+func (c *Client) AssignTSIGKey(ctx context.Context, key TSIGKey, zones ...string) error {
+	key.ZoneCount = 0
+	body := struct {
+		Key   TSIGKey  `json:"key"`
+		Zones []string `json:"zones"`
+	}{key, zones}
+
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, basePathV2+"keys/bulk-update", body, nil)
+}
+
+// RotateTSIGKey replaces oldKey with newKey on every zone that uses oldKey, and returns the names of those zones. The
+// masters of the zones must accept newKey before it is rotated in, or transfers will fail until they do.
+func (c *Client) RotateTSIGKey(ctx context.Context, oldKey TSIGKey, newKey TSIGKey) ([]string, error) {
+	zones, err := c.GetTSIGKeyZones(ctx, oldKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(zones) == 0 {
+		return nil, nil
+	}
+
+	if err := c.AssignTSIGKey(ctx, newKey, zones...); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
@@ -0,0 +1,137 @@
+package fastdns
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+const basePathV2 = "/config-dns/v2/"
+
+// ZoneType is the way a zone's records are maintained.
+type ZoneType string
+
+const (
+	// PrimaryZone records are edited through the API.
+	PrimaryZone ZoneType = "PRIMARY"
+
+	// SecondaryZone records are transferred from master name servers outside Akamai.
+	SecondaryZone ZoneType = "SECONDARY"
+
+	// AliasZone records are those of another zone, its Target.
+	AliasZone ZoneType = "ALIAS"
+)
+
+// ZoneConfig is the configuration of a zone, as opposed to its records.
+type ZoneConfig struct {
+	Zone string   `json:"zone"`
+	Type ZoneType `json:"type"`
+
+	// Masters are the addresses of the name servers that a secondary zone is transferred from.
+	Masters []string `json:"masters,omitempty"`
+
+	// TSIGKey, if set, authenticates transfers of a secondary zone from its masters.
+	TSIGKey *TSIGKey `json:"tsigKey,omitempty"`
+
+	// Target is the zone that an alias zone copies.
+	Target string `json:"target,omitempty"`
+
+	Comment            string `json:"comment,omitempty"`
+	SignAndServe       bool   `json:"signAndServe"`
+	EndCustomerID      string `json:"endCustomerId,omitempty"`
+	ContractID         string `json:"contractId,omitempty"`
+	ActivationState    string `json:"activationState,omitempty"`
+	LastActivationDate string `json:"lastActivationDate,omitempty"`
+	LastModifiedBy     string `json:"lastModifiedBy,omitempty"`
+	LastModifiedDate   string `json:"lastModifiedDate,omitempty"`
+	VersionID          string `json:"versionId,omitempty"`
+}
+
+// GetZoneConfig returns the configuration of a zone.
+func (c *Client) GetZoneConfig(ctx context.Context, zone string) (ZoneConfig, error) {
+	var z ZoneConfig
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, zonePath(zone), nil, &z)
+	if err != nil {
+		return ZoneConfig{}, err
+	}
+
+	return z, nil
+}
+
+// CreateZone creates a zone in a contract and group and returns its configuration. groupID may be empty if the
+// contract has a single group. For a secondary zone, set Masters and, if transfers are authenticated, TSIGKey:
+//
+//	z, err := client.CreateZone(ctx, fastdns.ZoneConfig{
+//		Zone:    "example.com",
+//		Type:    fastdns.SecondaryZone,
+//		Masters: []string{"192.0.2.1", "192.0.2.2"},
+//		TSIGKey: &fastdns.TSIGKey{Name: "transfer", Algorithm: "hmac-sha256", Secret: secret},
+//	}, "1-ABCDE", "12345")
+func (c *Client) CreateZone(ctx context.Context, z ZoneConfig, contractID string, groupID string) (ZoneConfig, error) {
+	query := url.Values{"contractId": {contractID}}
+	if groupID != "" {
+		query.Set("gid", groupID)
+	}
+
+	var created ZoneConfig
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, basePathV2+"zones?"+query.Encode(), z, &created)
+	if err != nil {
+		return ZoneConfig{}, err
+	}
+
+	return created, nil
+}
+
+// UpdateZoneConfig replaces the configuration of the zone named z.Zone, for example to change the masters of a
+// secondary zone, and returns the result.
+func (c *Client) UpdateZoneConfig(ctx context.Context, z ZoneConfig) (ZoneConfig, error) {
+	var updated ZoneConfig
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPut, zonePath(z.Zone), z, &updated)
+	if err != nil {
+		return ZoneConfig{}, err
+	}
+
+	return updated, nil
+}
+
+// ZoneTransferStatus describes the most recent transfers of a secondary zone from its masters.
+type ZoneTransferStatus struct {
+	Zone               string   `json:"zone"`
+	MasterIPs          []string `json:"masterIps"`
+	LastRefreshTime    string   `json:"lastRefreshTime"`
+	LastRefreshStatus  string   `json:"lastRefreshStatus"`
+	NextRefreshTime    string   `json:"nextRefreshTime"`
+	LastSOASerial      int64    `json:"lastSoaSerial"`
+	LastTransferTime   string   `json:"lastTransferTime"`
+	LastTransferResult string   `json:"lastTransferResult"`
+	LastNotifyTime     string   `json:"lastNotifyTime"`
+	LastNotifySerial   int64    `json:"lastNotifySerial"`
+}
+
+// GetZoneTransferStatus returns the transfer status of each of the given secondary zones.
+func (c *Client) GetZoneTransferStatus(ctx context.Context, zones ...string) ([]ZoneTransferStatus, error) {
+	var resp struct {
+		Zones []ZoneTransferStatus `json:"zones"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, basePathV2+"zones/zone-transfer-status", zoneList{Zones: zones}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Zones, nil
+}
+
+// zoneList is the request body of operations on several zones.
+type zoneList struct {
+	Zones []string `json:"zones"`
+}
+
+func zonePath(zone string) string {
+	return basePathV2 + "zones/" + url.PathEscape(zone)
+}
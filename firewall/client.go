@@ -3,7 +3,6 @@ package firewall
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,6 +12,7 @@ import (
 	"cloud.google.com/go/civil"
 	"github.com/corbaltcode/go-akamai"
 	"github.com/corbaltcode/go-akamai/internal/request"
+	"github.com/corbaltcode/go-akamai/prefixset"
 )
 
 const basePath = "/firewall-rules-manager/v1/"
@@ -20,6 +20,17 @@ const basePath = "/firewall-rules-manager/v1/"
 // A Client allows access to the Akamai Firewall Rules Notification API.
 type Client struct {
 	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
 }
 
 // GetCIDRBlocks returns all CIDR blocks for all services the client is
@@ -31,12 +42,12 @@ func (c *Client) GetCIDRBlocks() ([]CIDRBlock, error) {
 // GetCIDRBlocksWithContext returns all CIDR blocks for all services the client is
 // subscribed to.
 //
-// The context is used to determine whether debugging is enabled and to allow cancellation of the
-// request.
+// The context supplies the logger, hooks and HTTP client used for the request, and
+// allows cancellation of the request.
 func (c *Client) GetCIDRBlocksWithContext(ctx context.Context) ([]CIDRBlock, error) {
 	var respBlocks []cidrBlockResp
 
-	err := request.DoJSONWithContext(ctx, c.Credentials, http.MethodGet, basePath+"cidr-blocks", nil, &respBlocks)
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePath+"cidr-blocks", nil, &respBlocks)
 	if err != nil {
 		return nil, err
 	}
@@ -63,12 +74,12 @@ func (c *Client) GetService(id int) (Service, error) {
 
 // GetServiceWithContext returns information about a service by ID.
 //
-// The context is used to determine whether debugging is enabled and to allow cancellation of the
-// request.
+// The context supplies the logger, hooks and HTTP client used for the request, and
+// allows cancellation of the request.
 func (c *Client) GetServiceWithContext(ctx context.Context, id int) (Service, error) {
 	var service Service
 
-	err := request.DoJSONWithContext(ctx, c.Credentials, http.MethodGet, fmt.Sprintf("%sservices/%d", basePath, id), nil, &service)
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, fmt.Sprintf("%sservices/%d", basePath, id), nil, &service)
 	if err != nil {
 		return Service{}, err
 	}
@@ -99,10 +110,19 @@ type CIDRBlock struct {
 	LastAction    LastAction
 }
 
+// CIDRSet returns a set containing the CIDR of every block.
+func CIDRSet(blocks []CIDRBlock) *prefixset.Set {
+	s := &prefixset.Set{}
+	for _, b := range blocks {
+		s.Add(b.CIDR)
+	}
+	return s
+}
+
 func newCIDRBlockFromResp(ctx context.Context, r cidrBlockResp) (CIDRBlock, error) {
 	var err error
 	var v CIDRBlock
-	debug := akamai.DebugEnabled(ctx)
+	logger := akamai.Logger(ctx)
 
 	v.ID = r.CIDRID
 	v.ServiceID = r.ServiceID
@@ -110,9 +130,7 @@ func newCIDRBlockFromResp(ctx context.Context, r cidrBlockResp) (CIDRBlock, erro
 
 	v.CIDR, err = netip.ParsePrefix(r.CIDR + r.CIDRMask)
 	if err != nil {
-		if debug {
-			log.Printf("newCIDRBlockFromResp: error parsing CIDR and mask %s%s: %v", r.CIDR, r.CIDRMask, err)
-		}
+		logger.DebugContext(ctx, "error parsing CIDR", "cidr", r.CIDR+r.CIDRMask, "error", err)
 
 		return CIDRBlock{}, err
 	}
@@ -120,9 +138,7 @@ func newCIDRBlockFromResp(ctx context.Context, r cidrBlockResp) (CIDRBlock, erro
 	for _, portStr := range strings.Split(r.Port, ",") {
 		port, err := strconv.ParseUint(portStr, 10, 0)
 		if err != nil {
-			if debug {
-				log.Printf("newCIDRBlockFromResp: error parsing port %s: %v", portStr, err)
-			}
+			logger.DebugContext(ctx, "error parsing port", "port", portStr, "error", err)
 
 			return CIDRBlock{}, err
 		}
@@ -134,9 +150,7 @@ func newCIDRBlockFromResp(ctx context.Context, r cidrBlockResp) (CIDRBlock, erro
 	} else {
 		v.CreationDate, err = civil.ParseDate(r.CreationDate)
 		if err != nil {
-			if debug {
-				log.Printf("newCIDRBlockFromResp: error parsing creation date %s: %v", r.CreationDate, err)
-			}
+			logger.DebugContext(ctx, "error parsing creation date", "date", r.CreationDate, "error", err)
 
 			return CIDRBlock{}, err
 		}
@@ -146,9 +160,7 @@ func newCIDRBlockFromResp(ctx context.Context, r cidrBlockResp) (CIDRBlock, erro
 	} else {
 		v.EffectiveDate, err = civil.ParseDate(r.EffectiveDate)
 		if err != nil {
-			if debug {
-				log.Printf("newCIDRBlockFromResp: error parsing effective date %s: %v", r.EffectiveDate, err)
-			}
+			logger.DebugContext(ctx, "error parsing effective date", "date", r.EffectiveDate, "error", err)
 
 			return CIDRBlock{}, err
 		}
@@ -158,9 +170,7 @@ func newCIDRBlockFromResp(ctx context.Context, r cidrBlockResp) (CIDRBlock, erro
 	} else {
 		v.ChangeDate, err = civil.ParseDate(r.ChangeDate)
 		if err != nil {
-			if debug {
-				log.Printf("newCIDRBlockFromResp: error parsing change date %s: %v", r.ChangeDate, err)
-			}
+			logger.DebugContext(ctx, "error parsing change date", "date", r.ChangeDate, "error", err)
 
 			return CIDRBlock{}, err
 		}
@@ -168,17 +178,13 @@ func newCIDRBlockFromResp(ctx context.Context, r cidrBlockResp) (CIDRBlock, erro
 
 	v.MinIP, err = netip.ParseAddr(r.MinIP)
 	if err != nil {
-		if debug {
-			log.Printf("newCIDRBlockFromResp: error parsing min IP %s: %v", r.MinIP, err)
-		}
+		logger.DebugContext(ctx, "error parsing min IP", "ip", r.MinIP, "error", err)
 
 		return CIDRBlock{}, err
 	}
 	v.MaxIP, err = netip.ParseAddr(r.MaxIP)
 	if err != nil {
-		if debug {
-			log.Printf("newCIDRBlockFromResp: error parsing max IP %s: %v", r.MaxIP, err)
-		}
+		logger.DebugContext(ctx, "error parsing max IP", "ip", r.MaxIP, "error", err)
 
 		return CIDRBlock{}, err
 	}
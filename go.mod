@@ -5,6 +5,7 @@ go 1.24
 require (
 	cloud.google.com/go v0.99.0
 	gopkg.in/ini.v1 v1.66.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/stretchr/testify v1.7.0 // indirect
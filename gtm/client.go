@@ -0,0 +1,268 @@
+// Package gtm manages Akamai Global Traffic Management (GTM) domains, which route DNS traffic between datacenters
+// according to load, liveness and configured weights.
+//
+// Changes to a domain are accepted immediately but take a few minutes to propagate to Akamai's name servers. Every
+// update returns a ResponseStatus; use WaitForPropagation to wait for the change to take effect.
+package gtm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/poll"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+const basePath = "/config-gtm/v1/domains"
+
+// mediaType is the versioned media type of request and response bodies. The version determines which fields the API
+// accepts and returns.
+const mediaType = "application/vnd.config-gtm.v1.4+json"
+
+// A Client allows access to the Akamai GTM Configuration API.
+type Client struct {
+	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
+}
+
+// PropagationStatus is the progress of a change to a domain.
+type PropagationStatus string
+
+const (
+	PropagationPending  PropagationStatus = "PENDING"
+	PropagationComplete PropagationStatus = "COMPLETE"
+	PropagationDenied   PropagationStatus = "DENIED"
+)
+
+// ResponseStatus describes the most recent change to a domain.
+type ResponseStatus struct {
+	Message               string            `json:"message"`
+	ChangeID              string            `json:"changeId"`
+	PropagationStatus     PropagationStatus `json:"propagationStatus"`
+	PropagationStatusDate string            `json:"propagationStatusDate"`
+	PassingValidation     bool              `json:"passingValidation"`
+}
+
+// updateResp is the struct used to unmarshal the JSON response to a change, which holds the changed object and the
+// status of the domain.
+type updateResp[T any] struct {
+	Resource T              `json:"resource"`
+	Status   ResponseStatus `json:"status"`
+}
+
+type itemsResp[T any] struct {
+	Items []T `json:"items"`
+}
+
+// GetDomains returns a summary of each domain that the client can access.
+func (c *Client) GetDomains(ctx context.Context) ([]DomainSummary, error) {
+	var resp itemsResp[DomainSummary]
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, basePath, mediaTypeHeader(http.MethodGet), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Items, nil
+}
+
+// GetDomain returns a domain by name, including its properties, datacenters and resources.
+func (c *Client) GetDomain(ctx context.Context, domain string) (Domain, error) {
+	var d Domain
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, domainPath(domain), mediaTypeHeader(http.MethodGet), nil, &d)
+	if err != nil {
+		return Domain{}, err
+	}
+
+	return d, nil
+}
+
+// UpdateDomain replaces a domain, including all of its properties, datacenters and resources, with d.
+func (c *Client) UpdateDomain(ctx context.Context, d Domain) (Domain, ResponseStatus, error) {
+	return doUpdate[Domain](ctx, c, http.MethodPut, domainPath(d.Name), d)
+}
+
+// GetDomainStatus returns the status of the most recent change to a domain.
+func (c *Client) GetDomainStatus(ctx context.Context, domain string) (ResponseStatus, error) {
+	var status ResponseStatus
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, domainPath(domain)+"/status/current", mediaTypeHeader(http.MethodGet), nil, &status)
+	if err != nil {
+		return ResponseStatus{}, err
+	}
+
+	return status, nil
+}
+
+// WaitForPropagation polls the status of a domain every interval until the most recent change has propagated, and
+// returns the final status. It returns an error if the change is denied or the context is done. If interval is zero,
+// the status is polled every 30 seconds.
+func (c *Client) WaitForPropagation(ctx context.Context, domain string, interval time.Duration) (ResponseStatus, error) {
+	var status ResponseStatus
+
+	err := poll.Until(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		if status, err = c.GetDomainStatus(ctx, domain); err != nil {
+			return false, err
+		}
+
+		akamai.Logger(ctx).DebugContext(ctx, "GTM propagation status", "domain", domain, "change", status.ChangeID, "status", status.PropagationStatus)
+
+		if status.PropagationStatus == PropagationDenied {
+			return false, fmt.Errorf("change %s to GTM domain %s was denied: %s", status.ChangeID, domain, status.Message)
+		}
+		return status.PropagationStatus == PropagationComplete, nil
+	})
+
+	return status, err
+}
+
+// GetProperties returns the properties of a domain.
+func (c *Client) GetProperties(ctx context.Context, domain string) ([]Property, error) {
+	return doItems[Property](ctx, c, domainPath(domain)+"/properties")
+}
+
+// GetProperty returns a property of a domain by name.
+func (c *Client) GetProperty(ctx context.Context, domain string, name string) (Property, error) {
+	return doGet[Property](ctx, c, propertyPath(domain, name))
+}
+
+// UpdateProperty creates or replaces the property of a domain named p.Name.
+func (c *Client) UpdateProperty(ctx context.Context, domain string, p Property) (Property, ResponseStatus, error) {
+	return doUpdate[Property](ctx, c, http.MethodPut, propertyPath(domain, p.Name), p)
+}
+
+// DeleteProperty deletes a property of a domain by name.
+func (c *Client) DeleteProperty(ctx context.Context, domain string, name string) (ResponseStatus, error) {
+	return doDelete(ctx, c, propertyPath(domain, name))
+}
+
+// GetDatacenters returns the datacenters of a domain.
+func (c *Client) GetDatacenters(ctx context.Context, domain string) ([]Datacenter, error) {
+	return doItems[Datacenter](ctx, c, domainPath(domain)+"/datacenters")
+}
+
+// GetDatacenter returns a datacenter of a domain by ID.
+func (c *Client) GetDatacenter(ctx context.Context, domain string, id int) (Datacenter, error) {
+	return doGet[Datacenter](ctx, c, datacenterPath(domain, id))
+}
+
+// CreateDatacenter creates a datacenter in a domain and returns it with its assigned ID.
+func (c *Client) CreateDatacenter(ctx context.Context, domain string, dc Datacenter) (Datacenter, ResponseStatus, error) {
+	return doUpdate[Datacenter](ctx, c, http.MethodPost, domainPath(domain)+"/datacenters", dc)
+}
+
+// UpdateDatacenter replaces the datacenter of a domain with ID dc.DatacenterID.
+func (c *Client) UpdateDatacenter(ctx context.Context, domain string, dc Datacenter) (Datacenter, ResponseStatus, error) {
+	return doUpdate[Datacenter](ctx, c, http.MethodPut, datacenterPath(domain, dc.DatacenterID), dc)
+}
+
+// DeleteDatacenter deletes a datacenter of a domain by ID. A datacenter used by a property cannot be deleted.
+func (c *Client) DeleteDatacenter(ctx context.Context, domain string, id int) (ResponseStatus, error) {
+	return doDelete(ctx, c, datacenterPath(domain, id))
+}
+
+// GetResources returns the resources of a domain.
+func (c *Client) GetResources(ctx context.Context, domain string) ([]Resource, error) {
+	return doItems[Resource](ctx, c, domainPath(domain)+"/resources")
+}
+
+// GetResource returns a resource of a domain by name.
+func (c *Client) GetResource(ctx context.Context, domain string, name string) (Resource, error) {
+	return doGet[Resource](ctx, c, resourcePath(domain, name))
+}
+
+// UpdateResource creates or replaces the resource of a domain named r.Name.
+func (c *Client) UpdateResource(ctx context.Context, domain string, r Resource) (Resource, ResponseStatus, error) {
+	return doUpdate[Resource](ctx, c, http.MethodPut, resourcePath(domain, r.Name), r)
+}
+
+// DeleteResource deletes a resource of a domain by name.
+func (c *Client) DeleteResource(ctx context.Context, domain string, name string) (ResponseStatus, error) {
+	return doDelete(ctx, c, resourcePath(domain, name))
+}
+
+func doItems[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var resp itemsResp[T]
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, path, mediaTypeHeader(http.MethodGet), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Items, nil
+}
+
+func doGet[T any](ctx context.Context, c *Client, path string) (T, error) {
+	var v T
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodGet, path, mediaTypeHeader(http.MethodGet), nil, &v)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+func doUpdate[T any](ctx context.Context, c *Client, method string, path string, in T) (T, ResponseStatus, error) {
+	var resp updateResp[T]
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), method, path, mediaTypeHeader(method), in, &resp)
+	if err != nil {
+		var zero T
+		return zero, ResponseStatus{}, err
+	}
+
+	return resp.Resource, resp.Status, nil
+}
+
+func doDelete(ctx context.Context, c *Client, path string) (ResponseStatus, error) {
+	var resp updateResp[*struct{}]
+
+	err := request.DoJSONWithHeader(ctx, c.provider(), http.MethodDelete, path, mediaTypeHeader(http.MethodDelete), nil, &resp)
+	if err != nil {
+		return ResponseStatus{}, err
+	}
+
+	return resp.Status, nil
+}
+
+// mediaTypeHeader returns the headers that select the API version for a request with the given method.
+func mediaTypeHeader(method string) http.Header {
+	if method == http.MethodGet {
+		return http.Header{"Accept": {mediaType}}
+	}
+	return http.Header{"Accept": {mediaType}, "Content-Type": {mediaType}}
+}
+
+func domainPath(domain string) string {
+	return basePath + "/" + url.PathEscape(domain)
+}
+
+func propertyPath(domain string, name string) string {
+	return domainPath(domain) + "/properties/" + url.PathEscape(name)
+}
+
+func datacenterPath(domain string, id int) string {
+	return fmt.Sprintf("%s/datacenters/%d", domainPath(domain), id)
+}
+
+func resourcePath(domain string, name string) string {
+	return domainPath(domain) + "/resources/" + url.PathEscape(name)
+}
@@ -0,0 +1,127 @@
+package gtm
+
+type Domain struct {
+	Name                    string       `json:"name"`
+	Type                    string       `json:"type"`
+	LastModified            string       `json:"lastModified,omitempty"`
+	LastModifiedBy          string       `json:"lastModifiedBy,omitempty"`
+	ModificationComments    string       `json:"modificationComments,omitempty"`
+	LoadImbalancePercentage float64      `json:"loadImbalancePercentage,omitempty"`
+	DefaultErrorPenalty     int          `json:"defaultErrorPenalty,omitempty"`
+	DefaultTimeoutPenalty   int          `json:"defaultTimeoutPenalty,omitempty"`
+	CNameCoalescingEnabled  bool         `json:"cnameCoalescingEnabled"`
+	LoadFeedback            bool         `json:"loadFeedback"`
+	EmailNotificationList   []string     `json:"emailNotificationList,omitempty"`
+	Properties              []Property   `json:"properties,omitempty"`
+	Datacenters             []Datacenter `json:"datacenters,omitempty"`
+	Resources               []Resource   `json:"resources,omitempty"`
+}
+
+type DomainSummary struct {
+	Name                 string `json:"name"`
+	Status               string `json:"status"`
+	LastModified         string `json:"lastModified"`
+	LastModifiedBy       string `json:"lastModifiedBy"`
+	ChangeID             string `json:"changeId"`
+	ActivationState      string `json:"activationState"`
+	ModificationComments string `json:"modificationComments"`
+}
+
+type Property struct {
+	Name                      string          `json:"name"`
+	Type                      string          `json:"type"`
+	ScoreAggregationType      string          `json:"scoreAggregationType"`
+	HandoutMode               string          `json:"handoutMode"`
+	HandoutLimit              int             `json:"handoutLimit,omitempty"`
+	DynamicTTL                int             `json:"dynamicTTL,omitempty"`
+	StaticTTL                 int             `json:"staticTTL,omitempty"`
+	FailoverDelay             int             `json:"failoverDelay,omitempty"`
+	FailbackDelay             int             `json:"failbackDelay,omitempty"`
+	IPv6                      bool            `json:"ipv6"`
+	UseComputedTargets        bool            `json:"useComputedTargets"`
+	BalanceByDownloadScore    bool            `json:"balanceByDownloadScore"`
+	StickinessBonusPercentage int             `json:"stickinessBonusPercentage,omitempty"`
+	StickinessBonusConstant   int             `json:"stickinessBonusConstant,omitempty"`
+	LoadImbalancePercentage   float64         `json:"loadImbalancePercentage,omitempty"`
+	HealthThreshold           float64         `json:"healthThreshold,omitempty"`
+	HealthMax                 float64         `json:"healthMax,omitempty"`
+	HealthMultiplier          float64         `json:"healthMultiplier,omitempty"`
+	BackupCName               string          `json:"backupCName,omitempty"`
+	BackupIP                  string          `json:"backupIp,omitempty"`
+	MapName                   string          `json:"mapName,omitempty"`
+	Comments                  string          `json:"comments,omitempty"`
+	LastModified              string          `json:"lastModified,omitempty"`
+	TrafficTargets            []TrafficTarget `json:"trafficTargets,omitempty"`
+	LivenessTests             []LivenessTest  `json:"livenessTests,omitempty"`
+}
+
+type TrafficTarget struct {
+	DatacenterID int      `json:"datacenterId"`
+	Enabled      bool     `json:"enabled"`
+	Weight       float64  `json:"weight"`
+	Name         string   `json:"name,omitempty"`
+	Servers      []string `json:"servers,omitempty"`
+	HandoutCName string   `json:"handoutCName,omitempty"`
+}
+
+type LivenessTest struct {
+	Name                          string  `json:"name"`
+	TestObjectProtocol            string  `json:"testObjectProtocol"`
+	TestObject                    string  `json:"testObject,omitempty"`
+	TestObjectPort                int     `json:"testObjectPort,omitempty"`
+	TestInterval                  int     `json:"testInterval"`
+	TestTimeout                   float64 `json:"testTimeout"`
+	Disabled                      bool    `json:"disabled"`
+	HostHeader                    string  `json:"hostHeader,omitempty"`
+	RequestString                 string  `json:"requestString,omitempty"`
+	ResponseString                string  `json:"responseString,omitempty"`
+	HTTPError3xx                  bool    `json:"httpError3xx"`
+	HTTPError4xx                  bool    `json:"httpError4xx"`
+	HTTPError5xx                  bool    `json:"httpError5xx"`
+	DisableNonstandardPortWarning bool    `json:"disableNonstandardPortWarning"`
+	PeerCertificateVerification   bool    `json:"peerCertificateVerification"`
+	RecursionRequested            bool    `json:"recursionRequested"`
+	AnswersRequired               bool    `json:"answersRequired"`
+	ResourceType                  string  `json:"resourceType,omitempty"`
+	TestObjectUsername            string  `json:"testObjectUsername,omitempty"`
+	TestObjectPassword            string  `json:"testObjectPassword,omitempty"`
+	SSLClientCertificate          string  `json:"sslClientCertificate,omitempty"`
+	SSLClientPrivateKey           string  `json:"sslClientPrivateKey,omitempty"`
+}
+
+type Datacenter struct {
+	DatacenterID                  int     `json:"datacenterId,omitempty"`
+	Nickname                      string  `json:"nickname"`
+	City                          string  `json:"city,omitempty"`
+	StateOrProvince               string  `json:"stateOrProvince,omitempty"`
+	Country                       string  `json:"country,omitempty"`
+	Continent                     string  `json:"continent,omitempty"`
+	Latitude                      float64 `json:"latitude,omitempty"`
+	Longitude                     float64 `json:"longitude,omitempty"`
+	CloneOf                       int     `json:"cloneOf,omitempty"`
+	Virtual                       bool    `json:"virtual"`
+	CloudServerTargeting          bool    `json:"cloudServerTargeting"`
+	CloudServerHostHeaderOverride bool    `json:"cloudServerHostHeaderOverride"`
+}
+
+type Resource struct {
+	Name                        string             `json:"name"`
+	Type                        string             `json:"type"`
+	AggregationType             string             `json:"aggregationType"`
+	Description                 string             `json:"description,omitempty"`
+	HostHeader                  string             `json:"hostHeader,omitempty"`
+	LeastSquaresDecay           float64            `json:"leastSquaresDecay,omitempty"`
+	UpperBound                  int                `json:"upperBound,omitempty"`
+	MaxUMultiplicativeIncrement float64            `json:"maxUMultiplicativeIncrement,omitempty"`
+	DecayRate                   float64            `json:"decayRate,omitempty"`
+	LoadImbalancePercentage     float64            `json:"loadImbalancePercentage,omitempty"`
+	ResourceInstances           []ResourceInstance `json:"resourceInstances,omitempty"`
+}
+
+type ResourceInstance struct {
+	DatacenterID         int      `json:"datacenterId"`
+	UseDefaultLoadObject bool     `json:"useDefaultLoadObject"`
+	LoadObject           string   `json:"loadObject,omitempty"`
+	LoadObjectPort       int      `json:"loadObjectPort,omitempty"`
+	LoadServers          []string `json:"loadServers,omitempty"`
+}
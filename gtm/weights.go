@@ -0,0 +1,96 @@
+package gtm
+
+import "fmt"
+
+// Weights returns the weight of each traffic target of p by datacenter ID. Disabled targets are included.
+func (p *Property) Weights() map[int]float64 {
+	weights := make(map[int]float64, len(p.TrafficTargets))
+	for _, t := range p.TrafficTargets {
+		weights[t.DatacenterID] = t.Weight
+	}
+	return weights
+}
+
+// SetWeights sets the weights of the traffic targets of p for the datacenters in weights. Targets for other
+// datacenters are unchanged. It returns an error, without changing p, if p has no target for one of the datacenters or
+// a weight is negative.
+func (p *Property) SetWeights(weights map[int]float64) error {
+	for id, w := range weights {
+		if p.target(id) == nil {
+			return fmt.Errorf("property %s has no traffic target for datacenter %d", p.Name, id)
+		}
+		if w < 0 {
+			return fmt.Errorf("negative weight %v for datacenter %d", w, id)
+		}
+	}
+	for id, w := range weights {
+		p.target(id).Weight = w
+	}
+	return nil
+}
+
+// ShiftWeight moves amount of weight from the traffic target for datacenter from to the target for datacenter to,
+// limited to the weight that from has. It returns the weight actually moved. The destination target is enabled if it
+// was not.
+func (p *Property) ShiftWeight(from, to int, amount float64) (float64, error) {
+	src, dst := p.target(from), p.target(to)
+	if src == nil {
+		return 0, fmt.Errorf("property %s has no traffic target for datacenter %d", p.Name, from)
+	}
+	if dst == nil {
+		return 0, fmt.Errorf("property %s has no traffic target for datacenter %d", p.Name, to)
+	}
+	if amount < 0 {
+		return 0, fmt.Errorf("negative amount %v", amount)
+	}
+
+	amount = min(amount, src.Weight)
+	src.Weight -= amount
+	dst.Weight += amount
+	dst.Enabled = true
+
+	return amount, nil
+}
+
+// Drain moves all of the weight of the traffic target for a datacenter to the other enabled targets, in proportion to
+// their weights, or equally if they all have zero weight. The drained target is left enabled with zero weight so that
+// it can be restored with SetWeights. It returns an error if there is no other enabled target to take the traffic.
+func (p *Property) Drain(datacenterID int) error {
+	src := p.target(datacenterID)
+	if src == nil {
+		return fmt.Errorf("property %s has no traffic target for datacenter %d", p.Name, datacenterID)
+	}
+
+	var others []*TrafficTarget
+	var total float64
+	for i := range p.TrafficTargets {
+		t := &p.TrafficTargets[i]
+		if t.DatacenterID != datacenterID && t.Enabled {
+			others = append(others, t)
+			total += t.Weight
+		}
+	}
+	if len(others) == 0 {
+		return fmt.Errorf("property %s has no other enabled traffic target to drain datacenter %d to", p.Name, datacenterID)
+	}
+
+	for _, t := range others {
+		if total > 0 {
+			t.Weight += src.Weight * t.Weight / total
+		} else {
+			t.Weight += src.Weight / float64(len(others))
+		}
+	}
+	src.Weight = 0
+
+	return nil
+}
+
+func (p *Property) target(datacenterID int) *TrafficTarget {
+	for i := range p.TrafficTargets {
+		if p.TrafficTargets[i].DatacenterID == datacenterID {
+			return &p.TrafficTargets[i]
+		}
+	}
+	return nil
+}
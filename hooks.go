@@ -0,0 +1,74 @@
+package akamai
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// A RequestEvent describes an API request at one of the points where Hooks are called. Hooks receive the same event
+// throughout the life of a request attempt, so fields set by earlier hooks are visible to later ones.
+type RequestEvent struct {
+	// Method and Path are the HTTP method and the path, including any query string, of the API request.
+	Method string
+	Path   string
+
+	// Attempt is the 1-based number of the attempt being made to complete the request.
+	Attempt int
+
+	// Request is the outgoing HTTP request. It is nil if the request could not be created. In BeforeSign the
+	// Authorization header has not yet been set.
+	Request *http.Request
+
+	// StatusCode is the HTTP status of the response, or 0 if no response has been received.
+	StatusCode int
+
+	// Duration is the time from sending the request to reading the whole response. It is set in AfterResponse and,
+	// if the request was sent, in OnError.
+	Duration time.Duration
+
+	// Err is the error returned to the caller. It is set only in OnError.
+	Err error
+}
+
+// A HookFunc is called at a point in the life of an API request.
+type HookFunc func(ctx context.Context, e *RequestEvent)
+
+// Hooks are functions called at points in the life of each API request, for tracing and metrics. Any of them may be
+// nil. Hooks are called synchronously, so they should return quickly.
+type Hooks struct {
+	// BeforeSign is called after the HTTP request is created and before it is signed. Headers added to the request
+	// here are sent but are not part of the signature.
+	BeforeSign HookFunc
+
+	// BeforeSend is called after the request is signed and immediately before it is sent.
+	BeforeSend HookFunc
+
+	// AfterResponse is called after the whole response has been read, whatever its status.
+	AfterResponse HookFunc
+
+	// OnError is called when the request fails for any reason, including a non-2xx response status.
+	OnError HookFunc
+}
+
+// WithHooks returns a copy of the parent context in which API requests call hooks, in addition to any hooks set by a
+// parent context. Hooks set by the parent are called first.
+func WithHooks(ctx context.Context, hooks Hooks) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	existing, _ := ctx.Value(AkamaiHooks).([]Hooks)
+	all := make([]Hooks, 0, len(existing)+1)
+	all = append(all, existing...)
+	all = append(all, hooks)
+	return context.WithValue(ctx, AkamaiHooks, all)
+}
+
+// RequestHooks returns the hooks set in the context by WithHooks, in the order they should be called.
+func RequestHooks(ctx context.Context) []Hooks {
+	if ctx == nil {
+		return nil
+	}
+	hooks, _ := ctx.Value(AkamaiHooks).([]Hooks)
+	return hooks
+}
@@ -0,0 +1,47 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// WithHTTPClient returns a copy of the parent context in which API requests are sent with client instead of
+// http.DefaultClient. This can be used to set timeouts, proxies or a custom transport such as a test recorder.
+func WithHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, AkamaiHTTPClient, client)
+}
+
+// HTTPClient returns the client set in the context by WithHTTPClient, or http.DefaultClient if none is set.
+func HTTPClient(ctx context.Context) *http.Client {
+	if ctx != nil {
+		if client, ok := ctx.Value(AkamaiHTTPClient).(*http.Client); ok && client != nil {
+			return client
+		}
+	}
+	return http.DefaultClient
+}
+
+// ErrResponseTooLarge is returned when an API response body exceeds the limit set by WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response body exceeds maximum size")
+
+// WithMaxResponseSize returns a copy of the parent context in which API response bodies larger than n bytes, after
+// decompression, fail with ErrResponseTooLarge. A limit of zero or less means no limit.
+func WithMaxResponseSize(ctx context.Context, n int64) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, AkamaiMaxResponseSize, n)
+}
+
+// MaxResponseSize returns the limit set in the context by WithMaxResponseSize, or zero if there is no limit.
+func MaxResponseSize(ctx context.Context) int64 {
+	if ctx == nil {
+		return 0
+	}
+	n, _ := ctx.Value(AkamaiMaxResponseSize).(int64)
+	return n
+}
@@ -0,0 +1,166 @@
+// Package iam manages Akamai API clients and their credentials with the Identity and Access Management API, including
+// rotating the credentials that this library signs requests with.
+package iam
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+const basePath = "/identity-management/v3/api-clients"
+
+// Self is the client ID that refers to the API client whose credentials sign the request.
+const Self = "self"
+
+// A Client allows access to the Akamai Identity and Access Management API.
+type Client struct {
+	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
+}
+
+// APIClient represents an API client, which owns an access token and a set of credentials.
+type APIClient struct {
+	ClientID              string    `json:"clientId"`
+	ClientName            string    `json:"clientName"`
+	ClientDescription     string    `json:"clientDescription"`
+	ClientType            string    `json:"clientType"`
+	AccessToken           string    `json:"accessToken"`
+	ActiveCredentialCount int       `json:"activeCredentialCount"`
+	AuthorizedUsers       []string  `json:"authorizedUsers"`
+	NotificationEmails    []string  `json:"notificationEmails"`
+	CreatedBy             string    `json:"createdBy"`
+	CreatedDate           time.Time `json:"createdDate"`
+	IsLocked              bool      `json:"isLocked"`
+}
+
+// CredentialStatus is the state of a credential.
+type CredentialStatus string
+
+const (
+	CredentialActive   CredentialStatus = "ACTIVE"
+	CredentialInactive CredentialStatus = "INACTIVE"
+	CredentialDeleted  CredentialStatus = "DELETED"
+)
+
+// Credential represents a client token of an API client. The client secret is only available when the credential is
+// created.
+type Credential struct {
+	CredentialID int64            `json:"credentialId"`
+	ClientToken  string           `json:"clientToken"`
+	Status       CredentialStatus `json:"status"`
+	Description  string           `json:"description"`
+	CreatedOn    time.Time        `json:"createdOn"`
+	ExpiresOn    time.Time        `json:"expiresOn"`
+}
+
+// NewCredential is a newly created credential, including its client secret.
+type NewCredential struct {
+	Credential
+	ClientSecret string `json:"clientSecret"`
+}
+
+// Credentials returns credentials that sign requests with c, for the given API host and the access token of the API
+// client that owns c.
+func (c NewCredential) Credentials(host string, accessToken string) akamai.Credentials {
+	return akamai.Credentials{
+		ClientSecret: c.ClientSecret,
+		AccessToken:  accessToken,
+		ClientToken:  c.ClientToken,
+		Host:         host,
+	}
+}
+
+// GetAPIClients returns the API clients that the client can access.
+func (c *Client) GetAPIClients(ctx context.Context) ([]APIClient, error) {
+	var clients []APIClient
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePath, nil, &clients)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// GetAPIClient returns an API client by ID, or the requesting client if clientID is Self.
+func (c *Client) GetAPIClient(ctx context.Context, clientID string) (APIClient, error) {
+	var client APIClient
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, clientPath(clientID), nil, &client)
+	if err != nil {
+		return APIClient{}, err
+	}
+
+	return client, nil
+}
+
+// GetCredentials returns the credentials of an API client, without their secrets.
+func (c *Client) GetCredentials(ctx context.Context, clientID string) ([]Credential, error) {
+	var creds []Credential
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, clientPath(clientID)+"/credentials", nil, &creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
+
+// GetCredential returns a credential of an API client by ID, without its secret.
+func (c *Client) GetCredential(ctx context.Context, clientID string, credentialID int64) (Credential, error) {
+	var cred Credential
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, credentialPath(clientID, credentialID), nil, &cred)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	return cred, nil
+}
+
+// CreateCredential creates a credential for an API client. The returned client secret cannot be retrieved again.
+func (c *Client) CreateCredential(ctx context.Context, clientID string) (NewCredential, error) {
+	var cred NewCredential
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, clientPath(clientID)+"/credentials", nil, &cred)
+	if err != nil {
+		return NewCredential{}, err
+	}
+
+	return cred, nil
+}
+
+// DeactivateCredential deactivates a credential of an API client, so that requests signed with it are rejected. A
+// deactivated credential can be deleted.
+func (c *Client) DeactivateCredential(ctx context.Context, clientID string, credentialID int64) error {
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, credentialPath(clientID, credentialID)+"/deactivate", nil, nil)
+}
+
+// DeleteCredential deletes a credential of an API client. Only inactive credentials can be deleted.
+func (c *Client) DeleteCredential(ctx context.Context, clientID string, credentialID int64) error {
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodDelete, credentialPath(clientID, credentialID), nil, nil)
+}
+
+func clientPath(clientID string) string {
+	return basePath + "/" + url.PathEscape(clientID)
+}
+
+func credentialPath(clientID string, credentialID int64) string {
+	return fmt.Sprintf("%s/credentials/%d", clientPath(clientID), credentialID)
+}
@@ -0,0 +1,180 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/poll"
+)
+
+// DefaultVerifyTimeout is how long RotateCredentials waits for a new credential to be accepted when
+// RotateOptions.VerifyTimeout is zero. New credentials can take a short while to become usable.
+const DefaultVerifyTimeout = 2 * time.Minute
+
+// verifyInterval is how often a new credential is tried until it is accepted.
+const verifyInterval = 5 * time.Second
+
+// RotateOptions controls how RotateCredentials stores and verifies new credentials.
+type RotateOptions struct {
+	// Store saves the new credentials, for example with StoreEdgerc. It is required.
+	Store func(ctx context.Context, c akamai.Credentials) error
+
+	// Provider, if set, is used to read back the stored credentials for verification, so that a mistake in storing
+	// them is caught before the old credential is disabled. Providers with a Reload or Expire method, such as
+	// akamai.ReloadingEdgercProvider and akamai.CachingProvider, are refreshed first. If nil, the new credentials are
+	// verified directly.
+	Provider akamai.CredentialsProvider
+
+	// VerifyTimeout is how long to wait for the new credential to be accepted. If zero, DefaultVerifyTimeout is
+	// used.
+	VerifyTimeout time.Duration
+
+	// Delete deletes the old credential after deactivating it.
+	Delete bool
+}
+
+// Rotation describes a completed credential rotation.
+type Rotation struct {
+	// Old is the credential that was in use, which has been deactivated or deleted.
+	Old Credential
+
+	// New is the credential that replaced it.
+	New Credential
+
+	// Credentials are the new credentials, as stored.
+	Credentials akamai.Credentials
+}
+
+// StoreEdgerc returns a RotateOptions.Store function that writes credentials to a section of an .edgerc file.
+func StoreEdgerc(name string, section string) func(ctx context.Context, c akamai.Credentials) error {
+	return func(ctx context.Context, c akamai.Credentials) error {
+		return akamai.WriteEdgercSection(name, section, c)
+	}
+}
+
+// RotateCredentials replaces the credential that the client signs requests with. It creates a credential for the
+// same API client, stores it with opts.Store, verifies it with a signed call, and then deactivates the old credential
+// using the new one.
+//
+// Verification checks that the credentials read back from opts.Provider are the new ones, so that a Store that did
+// not persist them, or a provider still serving the old ones, is caught before the old credential is disabled.
+//
+// If a step after creating the new credential fails, the error is returned with a Rotation whose New field holds the
+// new credential, so that the caller can retry or clean up. If storing or verification fails, the old credential is
+// left active; the new credential remains active too and may be deactivated by hand.
+func (c *Client) RotateCredentials(ctx context.Context, opts RotateOptions) (Rotation, error) {
+	if opts.Store == nil {
+		return Rotation{}, fmt.Errorf("no store for the new credentials")
+	}
+
+	logger := akamai.Logger(ctx)
+
+	current, err := c.provider().Retrieve(ctx)
+	if err != nil {
+		return Rotation{}, err
+	}
+
+	old, err := c.findCredential(ctx, current.ClientToken)
+	if err != nil {
+		return Rotation{}, err
+	}
+
+	created, err := c.CreateCredential(ctx, Self)
+	if err != nil {
+		return Rotation{}, err
+	}
+	r := Rotation{Old: old, New: created.Credential, Credentials: created.Credentials(current.Host, current.AccessToken)}
+	logger.InfoContext(ctx, "created credential", "credential", created.CredentialID, "client_token", "sha256:"+akamai.Fingerprint(created.ClientToken))
+
+	if err := opts.Store(ctx, r.Credentials); err != nil {
+		return r, fmt.Errorf("storing credential %d: %w", created.CredentialID, err)
+	}
+
+	verifier := &Client{Credentials: r.Credentials, Provider: opts.Provider}
+	if err := verifier.verify(ctx, created.Credential, opts.VerifyTimeout); err != nil {
+		return r, fmt.Errorf("verifying credential %d: %w", created.CredentialID, err)
+	}
+	logger.InfoContext(ctx, "verified credential", "credential", created.CredentialID)
+
+	if err := verifier.DeactivateCredential(ctx, Self, old.CredentialID); err != nil {
+		return r, fmt.Errorf("deactivating credential %d: %w", old.CredentialID, err)
+	}
+	r.Old.Status = CredentialInactive
+	logger.InfoContext(ctx, "deactivated credential", "credential", old.CredentialID)
+
+	if opts.Delete {
+		if err := verifier.DeleteCredential(ctx, Self, old.CredentialID); err != nil {
+			return r, fmt.Errorf("deleting credential %d: %w", old.CredentialID, err)
+		}
+		r.Old.Status = CredentialDeleted
+		logger.InfoContext(ctx, "deleted credential", "credential", old.CredentialID)
+	}
+
+	return r, nil
+}
+
+// findCredential returns the credential of the requesting API client with the given client token.
+func (c *Client) findCredential(ctx context.Context, clientToken string) (Credential, error) {
+	creds, err := c.GetCredentials(ctx, Self)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	for _, cred := range creds {
+		if cred.ClientToken == clientToken {
+			return cred, nil
+		}
+	}
+
+	return Credential{}, fmt.Errorf("no credential with client token sha256:%s", akamai.Fingerprint(clientToken))
+}
+
+// verify retries a signed request for the new credential until it succeeds or timeout elapses. Each attempt first
+// checks that the provider serves the new credential's client token.
+func (c *Client) verify(ctx context.Context, cred Credential, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultVerifyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	err := poll.Until(ctx, verifyInterval, func(ctx context.Context) (bool, error) {
+		switch p := c.Provider.(type) {
+		case interface{ Reload(context.Context) error }:
+			if lastErr = p.Reload(ctx); lastErr != nil {
+				return false, nil
+			}
+		case interface{ Expire() }:
+			p.Expire()
+		}
+
+		var creds akamai.Credentials
+		if creds, lastErr = c.provider().Retrieve(ctx); lastErr != nil {
+			return false, nil
+		}
+		if creds.ClientToken != cred.ClientToken {
+			lastErr = fmt.Errorf("provider returned client token sha256:%s, want sha256:%s",
+				akamai.Fingerprint(creds.ClientToken), akamai.Fingerprint(cred.ClientToken))
+			akamai.Logger(ctx).DebugContext(ctx, "new credential not yet stored", "credential", cred.CredentialID, "error", lastErr)
+			return false, nil
+		}
+
+		var got Credential
+		if got, lastErr = c.GetCredential(ctx, Self, cred.CredentialID); lastErr != nil {
+			akamai.Logger(ctx).DebugContext(ctx, "new credential not yet accepted", "credential", cred.CredentialID, "error", lastErr)
+			return false, nil
+		}
+		if got.Status != CredentialActive {
+			return false, fmt.Errorf("credential is %s", got.Status)
+		}
+		return true, nil
+	})
+	if err != nil && lastErr != nil {
+		return lastErr
+	}
+
+	return err
+}
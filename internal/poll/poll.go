@@ -0,0 +1,36 @@
+// Package poll waits for long-running Akamai operations, such as activations, to finish.
+package poll
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultInterval is used when Until is given an interval of zero.
+const DefaultInterval = 30 * time.Second
+
+// Until calls check immediately and then every interval until it reports that the operation is done, returns an
+// error, or the context is done.
+func Until(ctx context.Context, interval time.Duration, check func(ctx context.Context) (bool, error)) error {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+
+		timer.Reset(interval)
+	}
+}
@@ -0,0 +1,258 @@
+package request
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/edgegrid"
+)
+
+// call holds the settings taken from the context for a single API call, which may take several attempts.
+type call struct {
+	method string
+	path   string
+
+	logger          *slog.Logger
+	hooks           []akamai.Hooks
+	clock           *akamai.Clock
+	client          *http.Client
+	maxResponseSize int64
+	redactedFields  []string
+}
+
+func newCall(ctx context.Context, method string, path string) *call {
+	return &call{
+		method:          method,
+		path:            path,
+		logger:          akamai.Logger(ctx).With("method", method, "path", path, "request_id", newRequestID()),
+		hooks:           akamai.RequestHooks(ctx),
+		clock:           akamai.RequestClock(ctx),
+		client:          akamai.HTTPClient(ctx),
+		maxResponseSize: akamai.MaxResponseSize(ctx),
+		redactedFields:  akamai.RedactedFields(ctx),
+	}
+}
+
+// attempt returns the event and logger for the given attempt number.
+func (c *call) attempt(n int) (*akamai.RequestEvent, *slog.Logger) {
+	return &akamai.RequestEvent{Method: c.method, Path: c.path, Attempt: n}, c.logger.With("attempt", n)
+}
+
+// send signs and sends one attempt at the request. body is the request body, of length contentLength or -1 if
+// unknown. signed holds the start of the body, at least edgegrid.DefaultMaxBody bytes of it if it is that long, and
+// is used for the content digest and debug logging. Values in header replace the default request headers.
+//
+// On success it returns the response, whose body is decoded according to its Content-Encoding, and the time the
+// request was sent.
+func (c *call) send(ctx context.Context, logger *slog.Logger, event *akamai.RequestEvent, p akamai.CredentialsProvider, header http.Header, body io.Reader, contentLength int64, signed []byte) (*http.Response, time.Time, error) {
+	creds, err := p.Retrieve(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "error retrieving credentials", "error", err)
+		return nil, time.Time{}, err
+	}
+
+	url := fmt.Sprintf("%s://%s%s", scheme, creds.Host, c.path)
+	req, err := http.NewRequestWithContext(ctx, c.method, url, body)
+	if err != nil {
+		logger.ErrorContext(ctx, "error creating request", "error", err)
+		return nil, time.Time{}, err
+	}
+	req.ContentLength = contentLength
+	req.Header.Add("Accept", "application/json")
+	if contentLength != 0 {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	event.Request = req
+
+	runHooks(ctx, c.hooks, func(h akamai.Hooks) akamai.HookFunc { return h.BeforeSign }, event)
+
+	signer := edgegrid.Signer{Credentials: creds, Clock: c.clock.Now}
+	authHeader, err := signer.Sign(c.method, scheme, c.path, nil, signed)
+	if err != nil {
+		logger.ErrorContext(ctx, "error generating auth header", "error", err)
+		return nil, time.Time{}, err
+	}
+	req.Header.Add("Authorization", authHeader)
+
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		logger.DebugContext(ctx, "sending request",
+			"host", creds.Host,
+			"headers", akamai.RedactHeaders(req.Header),
+			"body", string(akamai.RedactJSON(signed, c.redactedFields)))
+	}
+
+	runHooks(ctx, c.hooks, func(h akamai.Hooks) akamai.HookFunc { return h.BeforeSend }, event)
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		event.Duration = time.Since(start)
+		logger.ErrorContext(ctx, "request failed", "latency", event.Duration, "error", err)
+		return nil, start, err
+	}
+	event.StatusCode = resp.StatusCode
+
+	if err := c.decodeBody(resp); err != nil {
+		resp.Body.Close()
+		event.Duration = time.Since(start)
+		logger.ErrorContext(ctx, "error decoding response body", "status", resp.StatusCode, "latency", event.Duration, "error", err)
+		return nil, start, err
+	}
+
+	return resp, start, nil
+}
+
+// decodeBody replaces the body of resp with one that is decompressed according to its Content-Encoding and limited
+// to the maximum response size. Compression is normally negotiated and undone by the transport; a gzip-encoded body
+// only reaches here if the caller set Accept-Encoding itself or the transport does not decompress.
+func (c *call) decodeBody(resp *http.Response) error {
+	body := resp.Body
+
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return err
+		}
+		body = &multiCloser{Reader: zr, closers: []io.Closer{zr, resp.Body}}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+
+	if c.maxResponseSize > 0 {
+		body = &limitedBody{ReadCloser: body, remaining: c.maxResponseSize}
+	}
+
+	resp.Body = body
+	return nil
+}
+
+// finish is called once the whole response has been read, with out holding the body if it was buffered. It records
+// clock skew, calls AfterResponse hooks, logs the response and returns an error for a non-2xx status.
+func (c *call) finish(ctx context.Context, logger *slog.Logger, event *akamai.RequestEvent, resp *http.Response, start time.Time, out []byte) error {
+	received := time.Now()
+	event.Duration = received.Sub(start)
+
+	var skew time.Duration
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		if c.clock != nil {
+			skew = c.clock.Observe(date, start, received)
+		} else {
+			skew = akamai.MeasureSkew(date, start, received)
+		}
+	}
+
+	runHooks(ctx, c.hooks, func(h akamai.Hooks) akamai.HookFunc { return h.AfterResponse }, event)
+
+	logger.InfoContext(ctx, "request completed", "status", resp.StatusCode, "latency", event.Duration)
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		logger.DebugContext(ctx, "received response",
+			"status", resp.StatusCode,
+			"headers", resp.Header,
+			"body", string(akamai.RedactJSON(out, c.redactedFields)))
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := errors.New(string(out))
+		if resp.StatusCode == http.StatusUnauthorized && (skew > akamai.MaxClockSkew || skew < -akamai.MaxClockSkew) {
+			logger.WarnContext(ctx, "authentication failed with clock skew", "skew", skew)
+			return &akamai.ClockSkewError{Skew: skew, Err: err}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// failed calls OnError hooks for a failed attempt.
+func (c *call) failed(ctx context.Context, event *akamai.RequestEvent, err error) {
+	event.Err = err
+	runHooks(ctx, c.hooks, func(h akamai.Hooks) akamai.HookFunc { return h.OnError }, event)
+}
+
+// shouldRetry reports whether a failed attempt should be retried. A request rejected because of clock skew was not
+// processed, so it is safe to retry once the clock has been corrected.
+func (c *call) shouldRetry(ctx context.Context, logger *slog.Logger, attempt int, err error) bool {
+	var skewErr *akamai.ClockSkewError
+	if attempt == 1 && c.clock != nil && c.clock.Compensate && errors.As(err, &skewErr) {
+		logger.WarnContext(ctx, "retrying with clock skew compensation", "skew", skewErr.Skew)
+		return true
+	}
+	return false
+}
+
+// runHooks calls the hook selected by pick from each of hooks that sets it.
+func runHooks(ctx context.Context, hooks []akamai.Hooks, pick func(akamai.Hooks) akamai.HookFunc, event *akamai.RequestEvent) {
+	for _, h := range hooks {
+		if f := pick(h); f != nil {
+			f(ctx, event)
+		}
+	}
+}
+
+// newRequestID returns a random identifier used to correlate the log records of a single request.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// readPrefix reads up to n bytes from r and returns them along with a reader that yields the whole of r.
+func readPrefix(r io.Reader, n int) ([]byte, io.Reader, error) {
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(n)); err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	return buf.Bytes(), io.MultiReader(bytes.NewReader(buf.Bytes()), r), nil
+}
+
+// limitedBody fails with akamai.ErrResponseTooLarge once more than remaining bytes have been read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, akamai.ErrResponseTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), akamai.ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// multiCloser reads from Reader and closes each of closers in order.
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiCloser) Close() error {
+	var errs []error
+	for _, c := range m.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -4,94 +4,95 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/corbaltcode/go-akamai"
-	"github.com/corbaltcode/go-akamai/edgegrid"
 )
 
 const scheme = "https"
 
 // Do performs an HTTP request to the Akamai API with the given method, path, and body, and stores the response body in
-// out.
+// out. The request is signed with credentials from p.
 //
 // This is a compatibility wrapper around DoWithContext that uses context.Background() as the context.
-func Do(c akamai.Credentials, method string, path string, in []byte, out *[]byte) error {
-	return DoWithContext(context.Background(), c, method, path, in, out)
+func Do(p akamai.CredentialsProvider, method string, path string, in []byte, out *[]byte) error {
+	return DoWithContext(context.Background(), p, method, path, in, out)
 }
 
 // DoWithContext performs an HTTP request to the Akamai API with the given method, path, and body, and stores the
-// response body in out.
+// response body in out. The request is signed with credentials from p.
 //
-// The context is used to determine whether debugging is enabled and to allow cancellation of the request.
-func DoWithContext(ctx context.Context, c akamai.Credentials, method string, path string, in []byte, out *[]byte) error {
+// The context supplies the logger, hooks, HTTP client and clock, and allows cancellation of the request. If the
+// request is rejected as unauthorized while the local clock is skewed, a *akamai.ClockSkewError is returned.
+func DoWithContext(ctx context.Context, p akamai.CredentialsProvider, method string, path string, in []byte, out *[]byte) error {
+	return doBuffered(ctx, p, method, path, nil, in, out)
+}
+
+// doBuffered performs a buffered request, retrying it if appropriate. Values in header replace the default request
+// headers.
+func doBuffered(ctx context.Context, p akamai.CredentialsProvider, method string, path string, header http.Header, in []byte, out *[]byte) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	url := fmt.Sprintf("%s://%s%s", scheme, c.Host, path)
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(in))
-	if err != nil {
-		log.Printf("Error creating request: %v", err)
-		return err
-	}
-	req.Header.Add("Accept", "application/json")
+	c := newCall(ctx, method, path)
 
-	authHeader, err := edgegrid.GenerateAuthHeader(c, method, scheme, path, in)
-	if err != nil {
-		log.Printf("Error generating auth header: %v", err)
-		return err
-	}
-	req.Header.Add("Authorization", authHeader)
+	for attempt := 1; ; attempt++ {
+		event, logger := c.attempt(attempt)
 
-	if len(in) > 0 {
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("Content-Length", fmt.Sprintf("%d", len(in)))
+		err := do(ctx, c, logger, event, p, header, in, out)
+		if err == nil {
+			return nil
+		}
+
+		c.failed(ctx, event, err)
+		if !c.shouldRetry(ctx, logger, attempt, err) {
+			return err
+		}
 	}
+}
 
-	log.Printf("%s %s", method, url)
-	resp, err := http.DefaultClient.Do(req)
+// do performs a single attempt at a buffered request.
+func do(ctx context.Context, c *call, logger *slog.Logger, event *akamai.RequestEvent, p akamai.CredentialsProvider, header http.Header, in []byte, out *[]byte) error {
+	resp, start, err := c.send(ctx, logger, event, p, header, bytes.NewReader(in), int64(len(in)), in)
 	if err != nil {
-		log.Printf("Request failed: %v", err)
 		return err
 	}
-
 	defer resp.Body.Close()
+
 	*out, err = io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Error reading response body: %v", err)
+		event.Duration = time.Since(start)
+		logger.ErrorContext(ctx, "error reading response body", "status", resp.StatusCode, "latency", event.Duration, "error", err)
 		return err
 	}
 
-	if akamai.DebugEnabled(ctx) {
-		log.Printf("Response status: %d", resp.StatusCode)
-		log.Printf("Response body -------------------------\n%s\n-----------------------------", string(*out))
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return errors.New(string(*out))
-	}
-
-	return nil
+	return c.finish(ctx, logger, event, resp, start, *out)
 }
 
 // DoJSON performs an HTTP request to the Akamai API with the given method, path, and body, and unmarshals the JSON
 // response body into out.
 //
 // This is a compatibility wrapper around DoJSONWithContext that uses context.Background() as the context.
-func DoJSON(c akamai.Credentials, method string, path string, in interface{}, out interface{}) error {
-	return DoJSONWithContext(context.Background(), c, method, path, in, out)
+func DoJSON(p akamai.CredentialsProvider, method string, path string, in interface{}, out interface{}) error {
+	return DoJSONWithContext(context.Background(), p, method, path, in, out)
 }
 
 // DoJSONWithContext performs an HTTP request to the Akamai API with the given method, path, and body, and unmarshals
 // the JSON response body into out.
 //
-// The context is used to determine whether debugging is enabled and to allow cancellation of the request.
-func DoJSONWithContext(ctx context.Context, c akamai.Credentials, method string, path string, in interface{}, out interface{}) error {
+// The context supplies the logger, hooks, HTTP client and clock, and allows cancellation of the request. If the
+// request is rejected as unauthorized while the local clock is skewed, a *akamai.ClockSkewError is returned.
+func DoJSONWithContext(ctx context.Context, p akamai.CredentialsProvider, method string, path string, in interface{}, out interface{}) error {
+	return DoJSONWithHeader(ctx, p, method, path, nil, in, out)
+}
+
+// DoJSONWithHeader is like DoJSONWithContext, but values in header replace the default request headers. It is used
+// for APIs that version their request and response bodies with media types, such as GTM.
+func DoJSONWithHeader(ctx context.Context, p akamai.CredentialsProvider, method string, path string, header http.Header, in interface{}, out interface{}) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -107,7 +108,7 @@ func DoJSONWithContext(ctx context.Context, c akamai.Credentials, method string,
 		}
 	}
 
-	err = DoWithContext(ctx, c, method, path, bufIn, &bufOut)
+	err = doBuffered(ctx, p, method, path, header, bufIn, &bufOut)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,195 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/edgegrid"
+)
+
+// DoStreamWithContext performs an HTTP request to the Akamai API with the given method and path, streaming the request
+// body from in and returning the response body as a stream. in may be nil, and size is its length or -1 if unknown.
+// Only the first edgegrid.DefaultMaxBody bytes of in are buffered, to compute the content digest for signing. Values
+// in header replace the default request headers, such as Accept.
+//
+// A gzip-encoded response is decompressed. The caller must close the returned body; AfterResponse hooks are called
+// when it is closed. A response with a non-2xx status is read in full and returned as an error.
+//
+// The context supplies the logger, hooks, HTTP client, clock and maximum response size, and allows cancellation of
+// the request.
+func DoStreamWithContext(ctx context.Context, p akamai.CredentialsProvider, method string, path string, header http.Header, in io.Reader, size int64) (io.ReadCloser, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c := newCall(ctx, method, path)
+
+	var signed []byte
+	body := io.Reader(bytes.NewReader(nil))
+	if in != nil {
+		var err error
+		if signed, body, err = readPrefix(in, edgegrid.DefaultMaxBody); err != nil {
+			return nil, err
+		}
+	} else {
+		size = 0
+	}
+
+	for attempt := 1; ; attempt++ {
+		event, logger := c.attempt(attempt)
+
+		rc, err := doStream(ctx, c, logger, event, p, header, body, size, signed)
+		if err == nil {
+			return rc, nil
+		}
+
+		c.failed(ctx, event, err)
+		// Only an empty body can be sent again.
+		if in != nil || !c.shouldRetry(ctx, logger, attempt, err) {
+			return nil, err
+		}
+	}
+}
+
+// doStream performs a single attempt at a streamed request.
+func doStream(ctx context.Context, c *call, logger *slog.Logger, event *akamai.RequestEvent, p akamai.CredentialsProvider, header http.Header, in io.Reader, size int64, signed []byte) (io.ReadCloser, error) {
+	resp, start, err := c.send(ctx, logger, event, p, header, in, size, signed)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		out, err := io.ReadAll(resp.Body)
+		if err != nil {
+			event.Duration = time.Since(start)
+			logger.ErrorContext(ctx, "error reading response body", "status", resp.StatusCode, "latency", event.Duration, "error", err)
+			return nil, err
+		}
+		return nil, c.finish(ctx, logger, event, resp, start, out)
+	}
+
+	return &streamBody{ReadCloser: resp.Body, ctx: ctx, call: c, logger: logger, event: event, resp: resp, start: start}, nil
+}
+
+// streamBody is a successful response body that reports the end of the request when it is closed.
+type streamBody struct {
+	io.ReadCloser
+
+	ctx    context.Context
+	call   *call
+	logger *slog.Logger
+	event  *akamai.RequestEvent
+	resp   *http.Response
+	start  time.Time
+
+	err    error
+	closed bool
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+func (b *streamBody) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	err := b.ReadCloser.Close()
+	b.call.finish(b.ctx, b.logger, b.event, b.resp, b.start, nil)
+	if b.err != nil {
+		b.logger.ErrorContext(b.ctx, "error reading response body", "status", b.resp.StatusCode, "latency", b.event.Duration, "error", b.err)
+		b.call.failed(b.ctx, b.event, b.err)
+	}
+
+	return err
+}
+
+// DoJSONStreamWithContext performs an HTTP request to the Akamai API with the given method, path, and body, and calls
+// decode with a decoder reading the response body, so that large JSON responses can be processed incrementally. See
+// DecodeJSONArray and SeekJSONField.
+//
+// The context supplies the logger, hooks, HTTP client, clock and maximum response size, and allows cancellation of
+// the request.
+func DoJSONStreamWithContext(ctx context.Context, p akamai.CredentialsProvider, method string, path string, in interface{}, decode func(*json.Decoder) error) error {
+	var body io.Reader
+	var size int64
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body, size = bytes.NewReader(b), int64(len(b))
+	}
+
+	rc, err := DoStreamWithContext(ctx, p, method, path, nil, body, size)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return decode(json.NewDecoder(rc))
+}
+
+// DecodeJSONArray reads a JSON array from dec, calling fn with each element in turn.
+func DecodeJSONArray[T any](dec *json.Decoder, fn func(T) error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// SeekJSONField reads from dec up to the value of the named field of a JSON object, skipping other fields, so that
+// the next call to dec.Decode or DecodeJSONArray reads that value.
+func SeekJSONField(dec *json.Decoder, name string) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok == name {
+			return nil
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("field %q not found", name)
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("expected %v, got %v", want, tok)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+// Package metrics records per-endpoint latency and error metrics for Akamai API requests and exposes them in the
+// Prometheus text exposition format, without depending on a Prometheus client library.
+//
+// Register a Recorder's hooks on the context passed to API clients and serve the Recorder over HTTP:
+//
+//	rec := metrics.NewRecorder()
+//	ctx = akamai.WithHooks(ctx, rec.Hooks())
+//	http.Handle("/metrics", rec)
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/corbaltcode/go-akamai"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram buckets used by NewRecorder.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// A Recorder accumulates request metrics. It is safe for concurrent use.
+type Recorder struct {
+	// Buckets are the upper bounds, in seconds, of the latency histogram buckets, in increasing order. They must not
+	// be changed after the first request is recorded.
+	Buckets []float64
+
+	// Endpoint returns the endpoint label for a request path. If nil, Endpoint is used.
+	Endpoint func(path string) string
+
+	mu       sync.Mutex
+	requests map[requestKey]uint64
+	errors   map[seriesKey]uint64
+	latency  map[seriesKey]*histogram
+}
+
+type seriesKey struct {
+	method   string
+	endpoint string
+}
+
+type requestKey struct {
+	seriesKey
+	code int
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewRecorder returns a Recorder that uses DefaultBuckets.
+func NewRecorder() *Recorder {
+	return &Recorder{Buckets: DefaultBuckets}
+}
+
+// Hooks returns hooks that record the metrics of each request.
+func (r *Recorder) Hooks() akamai.Hooks {
+	return akamai.Hooks{
+		AfterResponse: r.afterResponse,
+		OnError:       r.onError,
+	}
+}
+
+func (r *Recorder) key(e *akamai.RequestEvent) seriesKey {
+	endpoint := Endpoint
+	if r.Endpoint != nil {
+		endpoint = r.Endpoint
+	}
+	return seriesKey{method: e.Method, endpoint: endpoint(e.Path)}
+}
+
+func (r *Recorder) afterResponse(ctx context.Context, e *akamai.RequestEvent) {
+	key := r.key(e)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.requests == nil {
+		r.requests = make(map[requestKey]uint64)
+		r.latency = make(map[seriesKey]*histogram)
+	}
+	r.requests[requestKey{key, e.StatusCode}]++
+
+	h := r.latency[key]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(r.Buckets))}
+		r.latency[key] = h
+	}
+	seconds := e.Duration.Seconds()
+	if i, _ := slices.BinarySearch(r.Buckets, seconds); i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += seconds
+}
+
+func (r *Recorder) onError(ctx context.Context, e *akamai.RequestEvent) {
+	key := r.key(e)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.errors == nil {
+		r.errors = make(map[seriesKey]uint64)
+	}
+	r.errors[key]++
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format. The metrics are copied before writing,
+// so a slow writer does not block requests from being recorded.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	snap := r.snapshot()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	cw.printf("# HELP akamai_requests_total Akamai API requests that received a response.\n")
+	cw.printf("# TYPE akamai_requests_total counter\n")
+	for _, k := range sortedKeys(snap.requests, func(a, b requestKey) int {
+		if c := compareSeries(a.seriesKey, b.seriesKey); c != 0 {
+			return c
+		}
+		return a.code - b.code
+	}) {
+		cw.printf("akamai_requests_total{%s,code=\"%d\"} %d\n", k.labels(), k.code, snap.requests[k])
+	}
+
+	cw.printf("# HELP akamai_request_errors_total Akamai API requests that failed, including non-2xx responses.\n")
+	cw.printf("# TYPE akamai_request_errors_total counter\n")
+	for _, k := range sortedKeys(snap.errors, compareSeries) {
+		cw.printf("akamai_request_errors_total{%s} %d\n", k.labels(), snap.errors[k])
+	}
+
+	cw.printf("# HELP akamai_request_duration_seconds Latency of Akamai API requests that received a response.\n")
+	cw.printf("# TYPE akamai_request_duration_seconds histogram\n")
+	for _, k := range sortedKeys(snap.latency, compareSeries) {
+		h := snap.latency[k]
+		var cumulative uint64
+		for i, le := range snap.buckets {
+			cumulative += h.counts[i]
+			cw.printf("akamai_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", k.labels(), formatFloat(le), cumulative)
+		}
+		cw.printf("akamai_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", k.labels(), h.count)
+		cw.printf("akamai_request_duration_seconds_sum{%s} %s\n", k.labels(), formatFloat(h.sum))
+		cw.printf("akamai_request_duration_seconds_count{%s} %d\n", k.labels(), h.count)
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// snapshot holds a copy of a Recorder's metrics.
+type snapshot struct {
+	buckets  []float64
+	requests map[requestKey]uint64
+	errors   map[seriesKey]uint64
+	latency  map[seriesKey]histogram
+}
+
+func (r *Recorder) snapshot() snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snap := snapshot{
+		buckets:  slices.Clone(r.Buckets),
+		requests: maps.Clone(r.requests),
+		errors:   maps.Clone(r.errors),
+		latency:  make(map[seriesKey]histogram, len(r.latency)),
+	}
+	for k, h := range r.latency {
+		snap.latency[k] = histogram{counts: slices.Clone(h.counts), count: h.count, sum: h.sum}
+	}
+
+	return snap
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+// Endpoint returns path without its query string and with each segment that identifies an object replaced by
+// "{id}", so that requests for different objects of the same kind share metrics. A segment identifies an object if it
+// follows a collection whose objects may have plain names, such as GTM "properties", or if it contains anything
+// other than letters and hyphens, such as the digits of "123", "prp_123" or a GUID, or the dots of a zone name. API
+// versions such as "v1" are kept.
+//
+// Set Recorder.Endpoint to map paths to route templates for APIs this does not cover.
+func Endpoint(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(path, "/")
+	out := make([]string, len(segments))
+	for i, s := range segments {
+		out[i] = s
+		if s != "" && ((i > 0 && collections[segments[i-1]]) || (!isVersion(s) && !isWord(s))) {
+			out[i] = "{id}"
+		}
+	}
+	return strings.Join(out, "/")
+}
+
+// collections are path segments of Akamai APIs that are followed by a name that may look like a resource, such as a
+// GTM property called "www" or the API client "self". Other IDs contain digits, underscores or dots.
+var collections = map[string]bool{
+	"api-clients":     true,
+	"as-maps":         true,
+	"cidr-maps":       true,
+	"geographic-maps": true,
+	"names":           true,
+	"properties":      true,
+	"resources":       true,
+	"versions":        true,
+}
+
+// isVersion reports whether s is an API version, such as "v1".
+func isVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	return strings.Trim(s[1:], "0123456789") == ""
+}
+
+// isWord reports whether s consists only of letters and hyphens, like the names of resources in Akamai APIs.
+func isWord(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func (k seriesKey) labels() string {
+	return fmt.Sprintf("method=\"%s\",endpoint=\"%s\"", escapeLabel(k.method), escapeLabel(k.endpoint))
+}
+
+func compareSeries(a, b seriesKey) int {
+	if c := strings.Compare(a.endpoint, b.endpoint); c != 0 {
+		return c
+	}
+	return strings.Compare(a.method, b.method)
+}
+
+func sortedKeys[K comparable, V any](m map[K]V, cmp func(a, b K) int) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, cmp)
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) printf(format string, args ...interface{}) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
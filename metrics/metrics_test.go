@@ -0,0 +1,29 @@
+package metrics
+
+import "testing"
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/papi/v1/properties/prp_123/versions/4/rules?contractId=ctr_X&groupId=grp_1", "/papi/v1/properties/{id}/versions/{id}/rules"},
+		{"/papi/v1/properties/prp_123/activations/atv_9", "/papi/v1/properties/{id}/activations/{id}"},
+		{"/config-dns/v2/zones/example.com/recordsets?showAll=true", "/config-dns/v2/zones/{id}/recordsets"},
+		{"/config-dns/v2/zones/example.com/names/www/types/A", "/config-dns/v2/zones/{id}/names/{id}/types/A"},
+		{"/config-dns/v2/zones/create-requests/0b2a0e6a-8d2c-4a3e-b5f1-7f0e0b1f1e2c", "/config-dns/v2/zones/create-requests/{id}"},
+		{"/config-gtm/v1/domains/example.akadns.net/properties/www", "/config-gtm/v1/domains/{id}/properties/{id}"},
+		{"/cps/v2/enrollments/10/deployments/production", "/cps/v2/enrollments/{id}/deployments/production"},
+		{"/identity-management/v3/api-clients/self/credentials/5/deactivate", "/identity-management/v3/api-clients/{id}/credentials/{id}/deactivate"},
+		{"/edgeworkers/v1/ids/5/versions/1.2", "/edgeworkers/v1/ids/{id}/versions/{id}"},
+		{"/network-list/v2/network-lists/123_ABC/environments/STAGING/activate", "/network-list/v2/network-lists/{id}/environments/STAGING/activate"},
+		{"/siteshield/v1/maps/123/acknowledge", "/siteshield/v1/maps/{id}/acknowledge"},
+		{"/ccu/v3/invalidate/url/production", "/ccu/v3/invalidate/url/production"},
+	}
+
+	for _, tt := range tests {
+		if got := Endpoint(tt.path); got != tt.want {
+			t.Errorf("Endpoint(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
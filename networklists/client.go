@@ -0,0 +1,349 @@
+// Package networklists manages Akamai network lists, the shared lists of IP addresses and countries used by security
+// policies to allow or block traffic.
+package networklists
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/poll"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+const basePath = "/network-list/v2/"
+
+// A Client allows access to the Akamai Network Lists API.
+type Client struct {
+	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
+}
+
+// ListType is the kind of element a network list holds.
+type ListType string
+
+const (
+	// IP lists hold IP addresses and CIDR blocks.
+	IP ListType = "IP"
+
+	// Geo lists hold ISO 3166 country codes.
+	Geo ListType = "GEO"
+)
+
+// Environment is the network to which a list is activated.
+type Environment string
+
+const (
+	Staging    Environment = "STAGING"
+	Production Environment = "PRODUCTION"
+)
+
+// NetworkList represents a network list.
+type NetworkList struct {
+	// ID is the list's unique ID, such as "1234_BLOCKLIST".
+	ID          string
+	Name        string
+	Type        ListType
+	Description string
+
+	// SyncPoint is the list's version. Updates must supply the SyncPoint of the version they modify, so that
+	// concurrent changes are detected.
+	SyncPoint    int
+	ReadOnly     bool
+	Shared       bool
+	ElementCount int
+
+	// ContractID and GroupID identify the access control group of a new list. They are not returned by the API.
+	ContractID string
+	GroupID    int
+
+	// Prefixes are the elements of an IP list. A single address is represented as a prefix of its full length.
+	Prefixes []netip.Prefix
+
+	// Countries are the elements of a Geo list.
+	Countries []string
+}
+
+func newListFromResp(ctx context.Context, r listResp) (NetworkList, error) {
+	logger := akamai.Logger(ctx)
+
+	l := NetworkList{
+		ID:           r.UniqueID,
+		Name:         r.Name,
+		Type:         ListType(r.Type),
+		Description:  r.Description,
+		SyncPoint:    r.SyncPoint,
+		ReadOnly:     r.ReadOnly,
+		Shared:       r.Shared,
+		ElementCount: r.ElementCount,
+	}
+
+	if l.Type != IP {
+		l.Countries = r.List
+		return l, nil
+	}
+
+	for _, element := range r.List {
+		prefix, err := parseElement(element)
+		if err != nil {
+			logger.DebugContext(ctx, "error parsing network list element", "list", r.UniqueID, "element", element, "error", err)
+
+			return NetworkList{}, err
+		}
+		l.Prefixes = append(l.Prefixes, prefix)
+	}
+
+	return l, nil
+}
+
+func newRespFromList(l NetworkList) listResp {
+	r := listResp{
+		UniqueID:    l.ID,
+		Name:        l.Name,
+		Type:        string(l.Type),
+		Description: l.Description,
+		SyncPoint:   l.SyncPoint,
+		ContractID:  l.ContractID,
+		GroupID:     l.GroupID,
+		List:        []string{},
+	}
+	if l.Type == IP {
+		r.List = formatPrefixes(l.Prefixes)
+	} else if l.Countries != nil {
+		r.List = l.Countries
+	}
+	return r
+}
+
+// listResp is the struct used to unmarshal the JSON response for a network list from the API, and to marshal
+// requests that create or update one.
+type listResp struct {
+	UniqueID     string   `json:"uniqueId,omitempty"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Description  string   `json:"description,omitempty"`
+	SyncPoint    int      `json:"syncPoint"`
+	ReadOnly     bool     `json:"readOnly,omitempty"`
+	Shared       bool     `json:"shared,omitempty"`
+	ElementCount int      `json:"elementCount,omitempty"`
+	ContractID   string   `json:"contractId,omitempty"`
+	GroupID      int      `json:"groupId,omitempty"`
+	List         []string `json:"list"`
+}
+
+type listsResp struct {
+	NetworkLists []listResp `json:"networkLists"`
+}
+
+// GetLists returns all network lists that the client can access, without their elements.
+func (c *Client) GetLists(ctx context.Context) ([]NetworkList, error) {
+	var resp listsResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePath+"network-lists", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make([]NetworkList, len(resp.NetworkLists))
+
+	for i, r := range resp.NetworkLists {
+		l, err := newListFromResp(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		lists[i] = l
+	}
+
+	return lists, nil
+}
+
+// GetList returns a network list by ID, including its elements.
+func (c *Client) GetList(ctx context.Context, id string) (NetworkList, error) {
+	return c.doList(ctx, http.MethodGet, listPath(id)+"?includeElements=true", nil)
+}
+
+// CreateList creates a network list with the name, type, description, elements, contract and group of l, and returns
+// the new list.
+func (c *Client) CreateList(ctx context.Context, l NetworkList) (NetworkList, error) {
+	return c.doList(ctx, http.MethodPost, basePath+"network-lists", newRespFromList(l))
+}
+
+// UpdateList replaces the name, description and elements of the list with l.ID, and returns the updated list.
+// l.SyncPoint must be that of the current version of the list, as returned by GetList; the update fails if the list
+// has changed since.
+func (c *Client) UpdateList(ctx context.Context, l NetworkList) (NetworkList, error) {
+	return c.doList(ctx, http.MethodPut, listPath(l.ID), newRespFromList(l))
+}
+
+// DeleteList deletes a network list by ID. Lists that are active or in use by a security policy cannot be deleted.
+func (c *Client) DeleteList(ctx context.Context, id string) error {
+	return request.DoJSONWithContext(ctx, c.provider(), http.MethodDelete, listPath(id), nil, nil)
+}
+
+// AppendPrefixes adds prefixes to an IP list, ignoring any it already contains, and returns the updated list.
+func (c *Client) AppendPrefixes(ctx context.Context, id string, prefixes ...netip.Prefix) (NetworkList, error) {
+	return c.appendElements(ctx, id, formatPrefixes(prefixes))
+}
+
+// RemovePrefix removes a prefix from an IP list and returns the updated list.
+func (c *Client) RemovePrefix(ctx context.Context, id string, prefix netip.Prefix) (NetworkList, error) {
+	return c.removeElement(ctx, id, formatPrefix(prefix))
+}
+
+// AppendCountries adds country codes to a Geo list, ignoring any it already contains, and returns the updated list.
+func (c *Client) AppendCountries(ctx context.Context, id string, countries ...string) (NetworkList, error) {
+	return c.appendElements(ctx, id, countries)
+}
+
+// RemoveCountry removes a country code from a Geo list and returns the updated list.
+func (c *Client) RemoveCountry(ctx context.Context, id string, country string) (NetworkList, error) {
+	return c.removeElement(ctx, id, country)
+}
+
+func (c *Client) appendElements(ctx context.Context, id string, elements []string) (NetworkList, error) {
+	body := struct {
+		List []string `json:"list"`
+	}{elements}
+
+	return c.doList(ctx, http.MethodPost, listPath(id)+"/append", body)
+}
+
+func (c *Client) removeElement(ctx context.Context, id string, element string) (NetworkList, error) {
+	return c.doList(ctx, http.MethodDelete, listPath(id)+"/elements?element="+url.QueryEscape(element), nil)
+}
+
+// doList performs a request whose response is a network list.
+func (c *Client) doList(ctx context.Context, method string, path string, in interface{}) (NetworkList, error) {
+	var resp listResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), method, path, in, &resp)
+	if err != nil {
+		return NetworkList{}, err
+	}
+
+	return newListFromResp(ctx, resp)
+}
+
+// ActivationStatus is the state of a list on an environment.
+type ActivationStatus string
+
+const (
+	StatusInactive            ActivationStatus = "INACTIVE"
+	StatusPendingActivation   ActivationStatus = "PENDING_ACTIVATION"
+	StatusActive              ActivationStatus = "ACTIVE"
+	StatusModified            ActivationStatus = "MODIFIED"
+	StatusPendingDeactivation ActivationStatus = "PENDING_DEACTIVATION"
+	StatusFailed              ActivationStatus = "FAILED"
+)
+
+// Activation describes the activation of a network list on an environment.
+type Activation struct {
+	ActivationID  int              `json:"activationId"`
+	NetworkListID string           `json:"uniqueId"`
+	Environment   Environment      `json:"environment"`
+	Status        ActivationStatus `json:"activationStatus"`
+
+	// SyncPoint is the version of the list being activated.
+	SyncPoint int `json:"syncPoint"`
+}
+
+// ActivationOptions are optional settings for an activation.
+type ActivationOptions struct {
+	Comments               string   `json:"comments,omitempty"`
+	NotificationRecipients []string `json:"notificationRecipients"`
+}
+
+// Activate starts activating the current version of a list on an environment and returns the activation. Use
+// WaitForActivation to wait for it to finish.
+func (c *Client) Activate(ctx context.Context, id string, env Environment, opts ActivationOptions) (Activation, error) {
+	var a Activation
+
+	if opts.NotificationRecipients == nil {
+		opts.NotificationRecipients = []string{}
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, fmt.Sprintf("%s/environments/%s/activate", listPath(id), env), opts, &a)
+	if err != nil {
+		return Activation{}, err
+	}
+
+	return a, nil
+}
+
+// GetActivationStatus returns the activation status of a list on an environment.
+func (c *Client) GetActivationStatus(ctx context.Context, id string, env Environment) (Activation, error) {
+	var a Activation
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, fmt.Sprintf("%s/environments/%s/status", listPath(id), env), nil, &a)
+	if err != nil {
+		return Activation{}, err
+	}
+
+	return a, nil
+}
+
+// WaitForActivation polls the activation status of a list on an environment every interval until it is active, and
+// returns the final status. It returns an error if the activation fails or the context is done. If interval is zero,
+// the status is polled every 30 seconds.
+func (c *Client) WaitForActivation(ctx context.Context, id string, env Environment, interval time.Duration) (Activation, error) {
+	var a Activation
+
+	err := poll.Until(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		if a, err = c.GetActivationStatus(ctx, id, env); err != nil {
+			return false, err
+		}
+
+		akamai.Logger(ctx).DebugContext(ctx, "network list activation status", "list", id, "environment", env, "status", a.Status)
+
+		if a.Status == StatusFailed {
+			return false, fmt.Errorf("activation of network list %s on %s failed", id, env)
+		}
+		return a.Status == StatusActive, nil
+	})
+
+	return a, err
+}
+
+func listPath(id string) string {
+	return basePath + "network-lists/" + url.PathEscape(id)
+}
+
+// parseElement parses an IP list element, which is either an address or a CIDR block.
+func parseElement(s string) (netip.Prefix, error) {
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	return netip.ParsePrefix(s)
+}
+
+// formatPrefix formats a prefix as an IP list element, writing single addresses without a prefix length as the API
+// does.
+func formatPrefix(p netip.Prefix) string {
+	if p.IsSingleIP() {
+		return p.Addr().String()
+	}
+	return p.String()
+}
+
+func formatPrefixes(prefixes []netip.Prefix) []string {
+	s := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		s[i] = formatPrefix(p)
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package networklists
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/corbaltcode/go-akamai/prefixset"
+)
+
+// A SyncPlan describes the changes needed to make an IP list cover exactly the addresses of a desired set of prefixes.
+type SyncPlan struct {
+	// List is the list as it was when the plan was made.
+	List NetworkList
+
+	// Add contains the desired addresses that the list does not cover.
+	Add []netip.Prefix
+
+	// Remove contains the addresses covered by the list that are not desired.
+	Remove []netip.Prefix
+}
+
+// HasChanges reports whether the plan adds or removes any prefixes.
+func (p SyncPlan) HasChanges() bool {
+	return len(p.Add) > 0 || len(p.Remove) > 0
+}
+
+// Diff computes the plan that changes the addresses covered by l to those covered by desired. Add and Remove hold the
+// addresses gained and lost as minimal, sorted lists of prefixes, so a desired prefix already covered by a broader
+// prefix in the list is not added.
+func Diff(l NetworkList, desired []netip.Prefix) SyncPlan {
+	current, want := prefixset.New(l.Prefixes...), prefixset.New(desired...)
+
+	return SyncPlan{
+		List:   l,
+		Add:    want.Difference(current).Prefixes(),
+		Remove: current.Difference(want).Prefixes(),
+	}
+}
+
+// PlanSync fetches an IP list and computes the plan that changes its prefixes to desired.
+func (c *Client) PlanSync(ctx context.Context, id string, desired []netip.Prefix) (SyncPlan, error) {
+	l, err := c.GetList(ctx, id)
+	if err != nil {
+		return SyncPlan{}, err
+	}
+	if l.Type != IP {
+		return SyncPlan{}, fmt.Errorf("network list %s is a %s list, not an IP list", id, l.Type)
+	}
+
+	return Diff(l, desired), nil
+}
+
+// Sync changes the prefixes of an IP list to the aggregate of desired and returns the plan that was applied along with
+// the updated list. The list is replaced in a single update guarded by its sync point, so it fails rather than
+// overwriting a concurrent change. If the list already covers exactly the desired addresses, it is not updated. The
+// list is not activated.
+func (c *Client) Sync(ctx context.Context, id string, desired []netip.Prefix) (SyncPlan, NetworkList, error) {
+	p, err := c.PlanSync(ctx, id, desired)
+	if err != nil {
+		return SyncPlan{}, NetworkList{}, err
+	}
+	if !p.HasChanges() {
+		return p, p.List, nil
+	}
+
+	l := p.List
+	l.Prefixes = prefixset.Aggregate(desired)
+
+	updated, err := c.UpdateList(ctx, l)
+	if err != nil {
+		return p, NetworkList{}, err
+	}
+
+	return p, updated, nil
+}
@@ -0,0 +1,158 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/poll"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+// Network is the Akamai network on which a property version is activated.
+type Network string
+
+const (
+	Staging    Network = "STAGING"
+	Production Network = "PRODUCTION"
+)
+
+// ActivationStatus is the progress of an activation.
+type ActivationStatus string
+
+const (
+	ActivationNew                 ActivationStatus = "NEW"
+	ActivationPending             ActivationStatus = "PENDING"
+	ActivationZone1               ActivationStatus = "ZONE_1"
+	ActivationZone2               ActivationStatus = "ZONE_2"
+	ActivationZone3               ActivationStatus = "ZONE_3"
+	ActivationActive              ActivationStatus = "ACTIVE"
+	ActivationFailed              ActivationStatus = "FAILED"
+	ActivationAborted             ActivationStatus = "ABORTED"
+	ActivationPendingDeactivation ActivationStatus = "PENDING_DEACTIVATION"
+	ActivationDeactivated         ActivationStatus = "DEACTIVATED"
+	ActivationInactive            ActivationStatus = "INACTIVE"
+)
+
+// ActivationType distinguishes activations from deactivations.
+type ActivationType string
+
+const (
+	ActivationTypeActivate   ActivationType = "ACTIVATE"
+	ActivationTypeDeactivate ActivationType = "DEACTIVATE"
+)
+
+// Activation represents the activation or deactivation of a property version on a network.
+type Activation struct {
+	ID              string           `json:"activationId"`
+	PropertyID      string           `json:"propertyId"`
+	PropertyVersion int              `json:"propertyVersion"`
+	Network         Network          `json:"network"`
+	Type            ActivationType   `json:"activationType"`
+	Status          ActivationStatus `json:"status"`
+	SubmitDate      time.Time        `json:"submitDate"`
+	UpdateDate      time.Time        `json:"updateDate"`
+	Note            string           `json:"note"`
+	NotifyEmails    []string         `json:"notifyEmails"`
+}
+
+// ActivationOptions are optional settings for an activation.
+type ActivationOptions struct {
+	Note         string
+	NotifyEmails []string
+
+	// AcknowledgeAllWarnings activates despite warnings about the rule tree. Without it, an activation with
+	// warnings is rejected.
+	AcknowledgeAllWarnings bool
+}
+
+// Activate starts activating a version of a property on a network and returns the activation. Use WaitForActivation
+// to wait for it to finish.
+func (c *Client) Activate(ctx context.Context, propertyID string, version int, network Network, opts ActivationOptions) (Activation, error) {
+	body := struct {
+		PropertyVersion        int      `json:"propertyVersion"`
+		Network                Network  `json:"network"`
+		Note                   string   `json:"note,omitempty"`
+		NotifyEmails           []string `json:"notifyEmails"`
+		AcknowledgeAllWarnings bool     `json:"acknowledgeAllWarnings"`
+	}{version, network, opts.Note, opts.NotifyEmails, opts.AcknowledgeAllWarnings}
+	if body.NotifyEmails == nil {
+		body.NotifyEmails = []string{}
+	}
+
+	var resp struct {
+		ActivationLink string `json:"activationLink"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, propertyPath(propertyID)+"/activations", body, &resp)
+	if err != nil {
+		return Activation{}, err
+	}
+
+	return c.GetActivation(ctx, propertyID, linkID(resp.ActivationLink))
+}
+
+// GetActivations returns the activations of a property, most recent first.
+func (c *Client) GetActivations(ctx context.Context, propertyID string) ([]Activation, error) {
+	var resp activationsResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, propertyPath(propertyID)+"/activations", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Activations.Items, nil
+}
+
+// GetActivation returns an activation of a property by ID.
+func (c *Client) GetActivation(ctx context.Context, propertyID string, activationID string) (Activation, error) {
+	var resp activationsResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, propertyPath(propertyID)+"/activations/"+url.PathEscape(activationID), nil, &resp)
+	if err != nil {
+		return Activation{}, err
+	}
+	if len(resp.Activations.Items) != 1 {
+		return Activation{}, fmt.Errorf("expected 1 activation, got %d", len(resp.Activations.Items))
+	}
+
+	return resp.Activations.Items[0], nil
+}
+
+type activationsResp struct {
+	Activations itemsResp[Activation] `json:"activations"`
+}
+
+// WaitForActivation polls an activation every interval until it is active, or also deactivated for a deactivation,
+// and returns its final state. It returns an error if the activation fails, is aborted or, for an activation, is
+// deactivated, or if the context is done. If interval is zero, the activation is polled every 30 seconds.
+func (c *Client) WaitForActivation(ctx context.Context, propertyID string, activationID string, interval time.Duration) (Activation, error) {
+	var a Activation
+
+	err := poll.Until(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		if a, err = c.GetActivation(ctx, propertyID, activationID); err != nil {
+			return false, err
+		}
+
+		akamai.Logger(ctx).DebugContext(ctx, "property activation status", "property", propertyID, "activation", activationID, "status", a.Status)
+
+		switch a.Status {
+		case ActivationActive:
+			return true, nil
+		case ActivationDeactivated:
+			if a.Type == ActivationTypeDeactivate {
+				return true, nil
+			}
+			fallthrough
+		case ActivationFailed, ActivationAborted:
+			return false, fmt.Errorf("activation %s of property %s version %d on %s: %s", activationID, propertyID, a.PropertyVersion, a.Network, a.Status)
+		}
+		return false, nil
+	})
+
+	return a, err
+}
@@ -0,0 +1,301 @@
+// Package papi reads Akamai property configurations and activates them with the Property Manager API (PAPI).
+package papi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+const basePath = "/papi/v1/"
+
+// A Client allows access to the Akamai Property Manager API.
+type Client struct {
+	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
+}
+
+// Contract represents a contract under which properties are created.
+type Contract struct {
+	ID       string `json:"contractId"`
+	TypeName string `json:"contractTypeName"`
+}
+
+// Group represents a group of properties.
+type Group struct {
+	ID            string   `json:"groupId"`
+	Name          string   `json:"groupName"`
+	ParentGroupID string   `json:"parentGroupId"`
+	ContractIDs   []string `json:"contractIds"`
+}
+
+// Property represents a property, the configuration of how the edge serves a set of hostnames.
+type Property struct {
+	ID         string `json:"propertyId"`
+	Name       string `json:"propertyName"`
+	ContractID string `json:"contractId"`
+	GroupID    string `json:"groupId"`
+	AssetID    string `json:"assetId"`
+	Note       string `json:"note"`
+
+	// LatestVersion is the most recently created version.
+	LatestVersion int `json:"latestVersion"`
+
+	// StagingVersion and ProductionVersion are the versions active on each network, or zero if none is.
+	StagingVersion    int `json:"stagingVersion"`
+	ProductionVersion int `json:"productionVersion"`
+}
+
+// VersionStatus is the activation state of a property version on a network.
+type VersionStatus string
+
+const (
+	VersionInactive    VersionStatus = "INACTIVE"
+	VersionPending     VersionStatus = "PENDING"
+	VersionActive      VersionStatus = "ACTIVE"
+	VersionDeactivated VersionStatus = "DEACTIVATED"
+)
+
+// Version represents a version of a property.
+type Version struct {
+	Version          int           `json:"propertyVersion"`
+	UpdatedByUser    string        `json:"updatedByUser"`
+	UpdatedDate      time.Time     `json:"updatedDate"`
+	StagingStatus    VersionStatus `json:"stagingStatus"`
+	ProductionStatus VersionStatus `json:"productionStatus"`
+	ProductID        string        `json:"productId"`
+	RuleFormat       string        `json:"ruleFormat"`
+	Note             string        `json:"note"`
+	Etag             string        `json:"etag"`
+}
+
+// Hostname represents a hostname served by a property version and the edge hostname it is mapped to.
+type Hostname struct {
+	CNAMEType      string `json:"cnameType"`
+	EdgeHostnameID string `json:"edgeHostnameId"`
+	CNAMEFrom      string `json:"cnameFrom"`
+	CNAMETo        string `json:"cnameTo"`
+}
+
+// RuleTree is the rule tree of a property version. Rules is kept as JSON so that behaviors and criteria of any rule
+// format survive a round trip unchanged.
+type RuleTree struct {
+	PropertyID      string          `json:"propertyId"`
+	PropertyVersion int             `json:"propertyVersion"`
+	Etag            string          `json:"etag"`
+	RuleFormat      string          `json:"ruleFormat"`
+	Rules           json.RawMessage `json:"rules"`
+
+	// Errors and Warnings are problems found when the rule tree was validated.
+	Errors   []RuleMessage `json:"errors"`
+	Warnings []RuleMessage `json:"warnings"`
+}
+
+// RuleMessage describes a problem with a rule tree.
+type RuleMessage struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Detail        string `json:"detail"`
+	ErrorLocation string `json:"errorLocation"`
+}
+
+type itemsResp[T any] struct {
+	Items []T `json:"items"`
+}
+
+// GetContracts returns the contracts that the client can access.
+func (c *Client) GetContracts(ctx context.Context) ([]Contract, error) {
+	var resp struct {
+		Contracts itemsResp[Contract] `json:"contracts"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePath+"contracts", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Contracts.Items, nil
+}
+
+// GetGroups returns the groups that the client can access.
+func (c *Client) GetGroups(ctx context.Context) ([]Group, error) {
+	var resp struct {
+		Groups itemsResp[Group] `json:"groups"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePath+"groups", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Groups.Items, nil
+}
+
+// GetProperties returns the properties in a contract and group.
+func (c *Client) GetProperties(ctx context.Context, contractID string, groupID string) ([]Property, error) {
+	var resp propertiesResp
+
+	query := url.Values{"contractId": {contractID}, "groupId": {groupID}}
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePath+"properties?"+query.Encode(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Properties.Items, nil
+}
+
+// GetProperty returns a property by ID.
+func (c *Client) GetProperty(ctx context.Context, propertyID string) (Property, error) {
+	var resp propertiesResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, propertyPath(propertyID), nil, &resp)
+	if err != nil {
+		return Property{}, err
+	}
+	if len(resp.Properties.Items) != 1 {
+		return Property{}, fmt.Errorf("expected 1 property, got %d", len(resp.Properties.Items))
+	}
+
+	return resp.Properties.Items[0], nil
+}
+
+type propertiesResp struct {
+	Properties itemsResp[Property] `json:"properties"`
+}
+
+// GetVersions returns all versions of a property, most recent first.
+func (c *Client) GetVersions(ctx context.Context, propertyID string) ([]Version, error) {
+	var resp versionsResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, propertyPath(propertyID)+"/versions", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Versions.Items, nil
+}
+
+// GetVersion returns a version of a property.
+func (c *Client) GetVersion(ctx context.Context, propertyID string, version int) (Version, error) {
+	var resp versionsResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, versionPath(propertyID, version), nil, &resp)
+	if err != nil {
+		return Version{}, err
+	}
+	if len(resp.Versions.Items) != 1 {
+		return Version{}, fmt.Errorf("expected 1 version, got %d", len(resp.Versions.Items))
+	}
+
+	return resp.Versions.Items[0], nil
+}
+
+type versionsResp struct {
+	Versions itemsResp[Version] `json:"versions"`
+}
+
+// CreateVersion creates a new version of a property as a copy of fromVersion and returns its number. If etag is not
+// empty, creation fails unless it matches the Etag of fromVersion, which guards against copying a version that has
+// changed since it was read.
+func (c *Client) CreateVersion(ctx context.Context, propertyID string, fromVersion int, etag string) (int, error) {
+	body := struct {
+		CreateFromVersion     int    `json:"createFromVersion"`
+		CreateFromVersionEtag string `json:"createFromVersionEtag,omitempty"`
+	}{fromVersion, etag}
+
+	var resp struct {
+		VersionLink string `json:"versionLink"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, propertyPath(propertyID)+"/versions", body, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := strconv.Atoi(linkID(resp.VersionLink))
+	if err != nil {
+		akamai.Logger(ctx).DebugContext(ctx, "error parsing version link", "link", resp.VersionLink, "error", err)
+
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// GetHostnames returns the hostnames served by a version of a property.
+func (c *Client) GetHostnames(ctx context.Context, propertyID string, version int) ([]Hostname, error) {
+	var resp struct {
+		Hostnames itemsResp[Hostname] `json:"hostnames"`
+	}
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, versionPath(propertyID, version)+"/hostnames", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Hostnames.Items, nil
+}
+
+// GetRuleTree returns the rule tree of a version of a property.
+func (c *Client) GetRuleTree(ctx context.Context, propertyID string, version int) (RuleTree, error) {
+	var tree RuleTree
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, versionPath(propertyID, version)+"/rules", nil, &tree)
+	if err != nil {
+		return RuleTree{}, err
+	}
+
+	return tree, nil
+}
+
+// UpdateRuleTree replaces the rule tree of a version of a property with rules, which is the value of the "rules"
+// member of a rule tree, and returns the validated result. Only versions that have never been activated can be
+// updated; use CreateVersion first otherwise. Check the Errors of the result before activating.
+func (c *Client) UpdateRuleTree(ctx context.Context, propertyID string, version int, rules json.RawMessage) (RuleTree, error) {
+	body := struct {
+		Rules json.RawMessage `json:"rules"`
+	}{rules}
+
+	var tree RuleTree
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPut, versionPath(propertyID, version)+"/rules", body, &tree)
+	if err != nil {
+		return RuleTree{}, err
+	}
+
+	return tree, nil
+}
+
+func propertyPath(propertyID string) string {
+	return basePath + "properties/" + url.PathEscape(propertyID)
+}
+
+func versionPath(propertyID string, version int) string {
+	return fmt.Sprintf("%s/versions/%d", propertyPath(propertyID), version)
+}
+
+// linkID returns the last path segment of a link returned by the API, such as the activation ID in
+// "/papi/v1/properties/prp_1/activations/atv_2?contractId=ctr_3".
+func linkID(link string) string {
+	link, _, _ = strings.Cut(link, "?")
+	return path.Base(link)
+}
@@ -0,0 +1,158 @@
+// Package prefixset provides sets of IP prefixes with support for set algebra, aggregation and address lookup. It
+// depends only on the standard library, so the service packages can build on it; see firewall.CIDRSet and
+// siteshield.CurrentCIDRSet.
+package prefixset
+
+import "net/netip"
+
+// A Set is a set of IP addresses represented as a collection of prefixes. IPv4 and IPv6 prefixes are stored
+// separately; an IPv4-mapped IPv6 prefix is treated as IPv6.
+//
+// The zero value is an empty set ready to use. A Set is not safe for concurrent modification.
+type Set struct {
+	v4 *node
+	v6 *node
+}
+
+// New returns a set containing the given prefixes. Invalid prefixes are ignored.
+func New(prefixes ...netip.Prefix) *Set {
+	s := &Set{}
+	for _, p := range prefixes {
+		s.Add(p)
+	}
+	return s
+}
+
+// Add adds all addresses in p to the set. Host bits in p are ignored. Invalid prefixes are ignored.
+func (s *Set) Add(p netip.Prefix) {
+	if !p.IsValid() {
+		return
+	}
+	p = p.Masked()
+	root := s.root(p.Addr())
+	*root = insert(*root, p.Addr().AsSlice(), 0, p.Bits())
+}
+
+// Remove removes all addresses in p from the set. Host bits in p are ignored. Invalid prefixes are ignored.
+func (s *Set) Remove(p netip.Prefix) {
+	if !p.IsValid() {
+		return
+	}
+	p = p.Masked()
+	root := s.root(p.Addr())
+	*root = remove(*root, p.Addr().AsSlice(), 0, p.Bits())
+}
+
+// Contains reports whether addr is in the set.
+func (s *Set) Contains(addr netip.Addr) bool {
+	if s == nil || !addr.IsValid() {
+		return false
+	}
+	n := *s.root(addr)
+	b := addr.AsSlice()
+	for depth := 0; n != nil; depth++ {
+		if n.full {
+			return true
+		}
+		if depth == len(b)*8 {
+			return false
+		}
+		n = n.children[bit(b, depth)]
+	}
+	return false
+}
+
+// ContainsPrefix reports whether every address in p is in the set.
+func (s *Set) ContainsPrefix(p netip.Prefix) bool {
+	if s == nil || !p.IsValid() {
+		return false
+	}
+	p = p.Masked()
+	n := *s.root(p.Addr())
+	b := p.Addr().AsSlice()
+	for depth := 0; n != nil; depth++ {
+		if n.full {
+			return true
+		}
+		if depth == p.Bits() {
+			return false
+		}
+		n = n.children[bit(b, depth)]
+	}
+	return false
+}
+
+// IsEmpty reports whether the set contains no addresses.
+func (s *Set) IsEmpty() bool {
+	return s == nil || (s.v4 == nil && s.v6 == nil)
+}
+
+// Prefixes returns the minimal list of prefixes covering exactly the addresses in the set. Adjacent prefixes are
+// aggregated and prefixes covered by others are omitted. IPv4 prefixes are returned before IPv6 prefixes, and each
+// family is sorted by address.
+func (s *Set) Prefixes() []netip.Prefix {
+	if s == nil {
+		return nil
+	}
+	var out []netip.Prefix
+	out = collect(out, s.v4, make([]byte, 4), 0)
+	out = collect(out, s.v6, make([]byte, 16), 0)
+	return out
+}
+
+// Clone returns a copy of the set.
+func (s *Set) Clone() *Set {
+	if s == nil {
+		return &Set{}
+	}
+	return &Set{v4: s.v4.clone(), v6: s.v6.clone()}
+}
+
+// Equal reports whether s and o contain the same addresses.
+func (s *Set) Equal(o *Set) bool {
+	a, b := s.Prefixes(), o.Prefixes()
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new set containing the addresses in s or o.
+func (s *Set) Union(o *Set) *Set {
+	u := s.Clone()
+	for _, p := range o.Prefixes() {
+		u.Add(p)
+	}
+	return u
+}
+
+// Difference returns a new set containing the addresses in s that are not in o.
+func (s *Set) Difference(o *Set) *Set {
+	d := s.Clone()
+	for _, p := range o.Prefixes() {
+		d.Remove(p)
+	}
+	return d
+}
+
+// Intersection returns a new set containing the addresses in both s and o.
+func (s *Set) Intersection(o *Set) *Set {
+	return s.Difference(s.Difference(o))
+}
+
+// Aggregate returns the minimal list of prefixes covering exactly the addresses in prefixes.
+func Aggregate(prefixes []netip.Prefix) []netip.Prefix {
+	return New(prefixes...).Prefixes()
+}
+
+func (s *Set) root(addr netip.Addr) **node {
+	if addr.Is4() {
+		return &s.v4
+	}
+	return &s.v6
+}
@@ -0,0 +1,208 @@
+package prefixset
+
+import (
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func prefixes(ss ...string) []netip.Prefix {
+	out := make([]netip.Prefix, len(ss))
+	for i, s := range ss {
+		out[i] = netip.MustParsePrefix(s)
+	}
+	return out
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []netip.Prefix
+		want []netip.Prefix
+	}{
+		{
+			name: "empty",
+			in:   nil,
+			want: nil,
+		},
+		{
+			name: "adjacent halves merge",
+			in:   prefixes("10.0.0.0/25", "10.0.0.128/25"),
+			want: prefixes("10.0.0.0/24"),
+		},
+		{
+			name: "merges cascade",
+			in:   prefixes("10.0.0.0/26", "10.0.0.64/26", "10.0.0.128/25"),
+			want: prefixes("10.0.0.0/24"),
+		},
+		{
+			name: "covered prefixes are dropped",
+			in:   prefixes("10.0.0.0/16", "10.0.1.0/24", "10.0.255.255/32"),
+			want: prefixes("10.0.0.0/16"),
+		},
+		{
+			name: "non-adjacent siblings stay apart",
+			in:   prefixes("10.0.0.128/25", "10.0.1.0/25"),
+			want: prefixes("10.0.0.128/25", "10.0.1.0/25"),
+		},
+		{
+			name: "duplicates and host bits",
+			in:   prefixes("192.0.2.7/24", "192.0.2.0/24", "192.0.2.0/24"),
+			want: prefixes("192.0.2.0/24"),
+		},
+		{
+			name: "sorted by address",
+			in:   prefixes("203.0.113.0/24", "192.0.2.0/24", "198.51.100.0/24"),
+			want: prefixes("192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24"),
+		},
+		{
+			name: "IPv4 before IPv6",
+			in:   prefixes("2001:db8::/32", "192.0.2.0/24"),
+			want: prefixes("192.0.2.0/24", "2001:db8::/32"),
+		},
+		{
+			name: "IPv6 halves merge",
+			in:   prefixes("2001:db8::/33", "2001:db8:8000::/33"),
+			want: prefixes("2001:db8::/32"),
+		},
+		{
+			name: "IPv4-mapped IPv6 stays apart from IPv4",
+			in:   prefixes("::ffff:192.0.2.0/120", "192.0.2.0/24"),
+			want: prefixes("192.0.2.0/24", "::ffff:192.0.2.0/120"),
+		},
+		{
+			name: "whole address space",
+			in:   prefixes("0.0.0.0/1", "128.0.0.0/1"),
+			want: prefixes("0.0.0.0/0"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Aggregate(tt.in); !slices.Equal(got, tt.want) {
+				t.Errorf("Aggregate(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b         []netip.Prefix
+		union        []netip.Prefix
+		intersection []netip.Prefix
+		difference   []netip.Prefix
+	}{
+		{
+			name:         "disjoint",
+			a:            prefixes("10.0.0.0/24"),
+			b:            prefixes("10.0.1.0/24"),
+			union:        prefixes("10.0.0.0/23"),
+			intersection: nil,
+			difference:   prefixes("10.0.0.0/24"),
+		},
+		{
+			name:         "nested",
+			a:            prefixes("10.0.0.0/22"),
+			b:            prefixes("10.0.1.0/24"),
+			union:        prefixes("10.0.0.0/22"),
+			intersection: prefixes("10.0.1.0/24"),
+			difference:   prefixes("10.0.0.0/24", "10.0.2.0/23"),
+		},
+		{
+			name:         "mixed families",
+			a:            prefixes("192.0.2.0/24", "2001:db8::/32"),
+			b:            prefixes("192.0.2.128/25", "2001:db8:8000::/33"),
+			union:        prefixes("192.0.2.0/24", "2001:db8::/32"),
+			intersection: prefixes("192.0.2.128/25", "2001:db8:8000::/33"),
+			difference:   prefixes("192.0.2.0/25", "2001:db8::/33"),
+		},
+		{
+			name:         "one family only",
+			a:            prefixes("192.0.2.0/24"),
+			b:            prefixes("2001:db8::/32"),
+			union:        prefixes("192.0.2.0/24", "2001:db8::/32"),
+			intersection: nil,
+			difference:   prefixes("192.0.2.0/24"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := New(tt.a...), New(tt.b...)
+
+			if got := a.Union(b).Prefixes(); !slices.Equal(got, tt.union) {
+				t.Errorf("Union = %v, want %v", got, tt.union)
+			}
+			if got := a.Intersection(b).Prefixes(); !slices.Equal(got, tt.intersection) {
+				t.Errorf("Intersection = %v, want %v", got, tt.intersection)
+			}
+			if got := a.Difference(b).Prefixes(); !slices.Equal(got, tt.difference) {
+				t.Errorf("Difference = %v, want %v", got, tt.difference)
+			}
+		})
+	}
+}
+
+func TestOperationsDoNotModifyInputs(t *testing.T) {
+	in := prefixes("10.0.0.0/25", "10.0.0.128/25", "2001:db8::/32")
+	inCopy := slices.Clone(in)
+
+	a := New(in...)
+	b := New(prefixes("10.0.0.0/26", "10.1.0.0/16", "2001:db8:1::/48")...)
+	aBefore, bBefore := a.Prefixes(), b.Prefixes()
+
+	a.Union(b)
+	a.Intersection(b)
+	a.Difference(b)
+	b.Difference(a)
+	Aggregate(in)
+
+	c := a.Clone()
+	c.Add(netip.MustParsePrefix("0.0.0.0/0"))
+	c.Remove(netip.MustParsePrefix("2001:db8::/33"))
+
+	if got := a.Prefixes(); !slices.Equal(got, aBefore) {
+		t.Errorf("a changed to %v, want %v", got, aBefore)
+	}
+	if got := b.Prefixes(); !slices.Equal(got, bBefore) {
+		t.Errorf("b changed to %v, want %v", got, bBefore)
+	}
+	if !slices.Equal(in, inCopy) {
+		t.Errorf("input slice changed to %v, want %v", in, inCopy)
+	}
+}
+
+func TestContains(t *testing.T) {
+	s := New(prefixes("10.0.0.0/24", "2001:db8::/32")...)
+	s.Remove(netip.MustParsePrefix("10.0.0.128/26"))
+
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"10.0.0.0", true},
+		{"10.0.0.127", true},
+		{"10.0.0.128", false},
+		{"10.0.0.191", false},
+		{"10.0.0.192", true},
+		{"10.0.1.0", false},
+		{"2001:db8::1", true},
+		{"2001:db9::", false},
+		{"::ffff:10.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := s.Contains(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("Contains(%s) = %t, want %t", tt.addr, got, tt.want)
+		}
+	}
+
+	if !s.ContainsPrefix(netip.MustParsePrefix("10.0.0.0/25")) {
+		t.Error("ContainsPrefix(10.0.0.0/25) = false, want true")
+	}
+	if s.ContainsPrefix(netip.MustParsePrefix("10.0.0.0/24")) {
+		t.Error("ContainsPrefix(10.0.0.0/24) = true, want false")
+	}
+}
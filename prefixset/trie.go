@@ -0,0 +1,84 @@
+package prefixset
+
+import "net/netip"
+
+// node is a node in a binary radix trie keyed on address bits. A full node covers every address below it and has
+// no children. Empty subtrees are always pruned, so a non-nil node always covers at least one address.
+type node struct {
+	children [2]*node
+	full     bool
+}
+
+func bit(b []byte, i int) int {
+	return int(b[i/8]>>(7-i%8)) & 1
+}
+
+func setBit(b []byte, i int, v int) {
+	mask := byte(1) << (7 - i%8)
+	if v == 1 {
+		b[i/8] |= mask
+	} else {
+		b[i/8] &^= mask
+	}
+}
+
+// insert adds the prefix of the given length to the subtree n at depth and returns the new subtree.
+func insert(n *node, addr []byte, depth, bits int) *node {
+	if n != nil && n.full {
+		return n
+	}
+	if depth == bits {
+		return &node{full: true}
+	}
+	if n == nil {
+		n = &node{}
+	}
+	b := bit(addr, depth)
+	n.children[b] = insert(n.children[b], addr, depth+1, bits)
+	if n.children[0] != nil && n.children[0].full && n.children[1] != nil && n.children[1].full {
+		return &node{full: true}
+	}
+	return n
+}
+
+// remove removes the prefix of the given length from the subtree n at depth and returns the new subtree.
+func remove(n *node, addr []byte, depth, bits int) *node {
+	if n == nil || depth == bits {
+		return nil
+	}
+	if n.full {
+		n = &node{children: [2]*node{{full: true}, {full: true}}}
+	}
+	b := bit(addr, depth)
+	n.children[b] = remove(n.children[b], addr, depth+1, bits)
+	if n.children[0] == nil && n.children[1] == nil {
+		return nil
+	}
+	return n
+}
+
+func collect(out []netip.Prefix, n *node, addr []byte, depth int) []netip.Prefix {
+	if n == nil {
+		return out
+	}
+	if n.full {
+		a, _ := netip.AddrFromSlice(addr)
+		return append(out, netip.PrefixFrom(a, depth))
+	}
+	for b := 0; b < 2; b++ {
+		setBit(addr, depth, b)
+		out = collect(out, n.children[b], addr, depth+1)
+	}
+	setBit(addr, depth, 0)
+	return out
+}
+
+func (n *node) clone() *node {
+	if n == nil {
+		return nil
+	}
+	return &node{
+		children: [2]*node{n.children[0].clone(), n.children[1].clone()},
+		full:     n.full,
+	}
+}
@@ -0,0 +1,205 @@
+package akamai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A CredentialsProvider supplies credentials for signing requests. Service clients call Retrieve before each
+// request, so a provider may return different credentials over time to support rotation.
+type CredentialsProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// Retrieve returns c, allowing a fixed set of credentials to be used as a CredentialsProvider.
+func (c Credentials) Retrieve(ctx context.Context) (Credentials, error) {
+	return c, nil
+}
+
+// validate returns an error naming the first missing field of c.
+func (c Credentials) validate() error {
+	switch {
+	case c.ClientToken == "":
+		return errors.New("missing client_token")
+	case c.ClientSecret == "":
+		return errors.New("missing client_secret")
+	case c.AccessToken == "":
+		return errors.New("missing access_token")
+	case c.Host == "":
+		return errors.New("missing host")
+	}
+	return nil
+}
+
+// EdgercProvider reads credentials from a section of an .edgerc file on each call to Retrieve.
+type EdgercProvider struct {
+	Path    string
+	Section string
+}
+
+// Retrieve loads credentials from the file.
+func (p EdgercProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	return LoadCredentialsFromEdgercFileWithContext(ctx, p.Path, p.Section)
+}
+
+// EnvProvider reads credentials from environment variables named AKAMAI_CLIENT_TOKEN, AKAMAI_CLIENT_SECRET,
+// AKAMAI_ACCESS_TOKEN and AKAMAI_HOST. If Section is set, its upper-cased name is inserted after AKAMAI_, as in
+// AKAMAI_DEFAULT_HOST.
+type EnvProvider struct {
+	Section string
+}
+
+// Retrieve reads credentials from the environment.
+func (p EnvProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	prefix := "AKAMAI_"
+	if p.Section != "" {
+		prefix += strings.ToUpper(strings.ReplaceAll(p.Section, "-", "_")) + "_"
+	}
+
+	c := Credentials{
+		ClientToken:  os.Getenv(prefix + "CLIENT_TOKEN"),
+		ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+		AccessToken:  os.Getenv(prefix + "ACCESS_TOKEN"),
+		Host:         os.Getenv(prefix + "HOST"),
+	}
+	if err := c.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("%s variables: %w", prefix, err)
+	}
+
+	return c, nil
+}
+
+// FileProvider reads credentials from a JSON or YAML file with the keys client_token, client_secret, access_token
+// and host. Files ending in .yaml or .yml are parsed as YAML; all others as JSON.
+type FileProvider struct {
+	Path string
+}
+
+// credentialsFile is the format read by FileProvider.
+type credentialsFile struct {
+	ClientToken  string `json:"client_token" yaml:"client_token"`
+	ClientSecret string `json:"client_secret" yaml:"client_secret"`
+	AccessToken  string `json:"access_token" yaml:"access_token"`
+	Host         string `json:"host" yaml:"host"`
+}
+
+// Retrieve loads credentials from the file.
+func (p FileProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	b, err := os.ReadFile(p.Path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	var f credentialsFile
+	switch strings.ToLower(filepath.Ext(p.Path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &f)
+	default:
+		err = json.Unmarshal(b, &f)
+	}
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%s: %w", p.Path, err)
+	}
+
+	c := Credentials{
+		ClientToken:  f.ClientToken,
+		ClientSecret: f.ClientSecret,
+		AccessToken:  f.AccessToken,
+		Host:         f.Host,
+	}
+	if err := c.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("%s: %w", p.Path, err)
+	}
+
+	return c, nil
+}
+
+// DirectoryProvider reads credentials from a directory containing one file per field, named client_token,
+// client_secret, access_token and host, as produced by mounting a Kubernetes secret or a Vault agent template.
+// Leading and trailing whitespace in each file is ignored.
+type DirectoryProvider struct {
+	Dir string
+}
+
+// Retrieve loads credentials from the directory.
+func (p DirectoryProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	read := func(name string) (string, error) {
+		b, err := os.ReadFile(filepath.Join(p.Dir, name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+
+	var c Credentials
+	var err error
+	if c.ClientToken, err = read("client_token"); err != nil {
+		return Credentials{}, err
+	}
+	if c.ClientSecret, err = read("client_secret"); err != nil {
+		return Credentials{}, err
+	}
+	if c.AccessToken, err = read("access_token"); err != nil {
+		return Credentials{}, err
+	}
+	if c.Host, err = read("host"); err != nil {
+		return Credentials{}, err
+	}
+	if err := c.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("%s: %w", p.Dir, err)
+	}
+
+	return c, nil
+}
+
+// CachingProvider wraps another provider and reuses the credentials it returns until they are older than Expiry.
+// Errors are not cached. A CachingProvider is safe for concurrent use and must not be copied after first use.
+type CachingProvider struct {
+	Provider CredentialsProvider
+	Expiry   time.Duration
+
+	mu        sync.Mutex
+	creds     Credentials
+	retrieved time.Time
+}
+
+// NewCachingProvider returns a provider that caches credentials from p for the given duration.
+func NewCachingProvider(p CredentialsProvider, expiry time.Duration) *CachingProvider {
+	return &CachingProvider{Provider: p, Expiry: expiry}
+}
+
+// Retrieve returns the cached credentials, refreshing them from the wrapped provider if they have expired.
+func (p *CachingProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.retrieved.IsZero() && time.Since(p.retrieved) < p.Expiry {
+		return p.creds, nil
+	}
+
+	c, err := p.Provider.Retrieve(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	p.creds = c
+	p.retrieved = time.Now()
+
+	return c, nil
+}
+
+// Expire discards the cached credentials so that the next call to Retrieve refreshes them.
+func (p *CachingProvider) Expire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.retrieved = time.Time{}
+}
@@ -0,0 +1,239 @@
+// Package purge removes content from the Akamai edge cache with the Fast Purge (CCU v3) API.
+//
+// Content can be invalidated, which makes the edge revalidate it with the origin before serving it again, or deleted,
+// which removes it outright. It is selected by URL, CP code or cache tag, on the staging or production network.
+// Long lists are split into several requests so that each stays within the API's size limit.
+package purge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/corbaltcode/go-akamai"
+	"github.com/corbaltcode/go-akamai/internal/request"
+)
+
+const basePath = "/ccu/v3/"
+
+// MaxRequestSize is the largest request body, in bytes, accepted by the Fast Purge API. Lists of objects whose
+// encoding would exceed it are sent in several requests.
+const MaxRequestSize = 50000
+
+// Action is what happens to purged content.
+type Action string
+
+const (
+	// Invalidate marks content as stale, so that the edge revalidates it with the origin on the next request.
+	Invalidate Action = "invalidate"
+
+	// Delete removes content from the edge, so that the next request fetches it from the origin.
+	Delete Action = "delete"
+)
+
+// ObjectType is the way purged content is selected.
+type ObjectType string
+
+const (
+	URL    ObjectType = "url"
+	CPCode ObjectType = "cpcode"
+	Tag    ObjectType = "tag"
+)
+
+// Network is the Akamai network on which content is purged.
+type Network string
+
+const (
+	Staging    Network = "staging"
+	Production Network = "production"
+)
+
+// A Client allows access to the Akamai Fast Purge API.
+type Client struct {
+	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
+}
+
+// Result describes one accepted purge request.
+type Result struct {
+	// PurgeID identifies the request.
+	PurgeID string
+
+	// SupportID identifies the request to Akamai support.
+	SupportID string
+
+	// Detail is the API's description of the outcome.
+	Detail string
+
+	// Objects are the URLs, CP codes or cache tags purged by the request.
+	Objects []string
+
+	// EstimatedCompletion is when the purge is expected to have taken effect across the network.
+	EstimatedCompletion time.Time
+}
+
+// EstimatedCompletion returns the latest estimated completion time of results, which is when all of them are expected
+// to have taken effect. It returns the zero time if results is empty.
+func EstimatedCompletion(results []Result) time.Time {
+	var latest time.Time
+	for _, r := range results {
+		if r.EstimatedCompletion.After(latest) {
+			latest = r.EstimatedCompletion
+		}
+	}
+	return latest
+}
+
+// InvalidateURLs invalidates content by URL. URLs must be absolute, including the scheme and hostname.
+func (c *Client) InvalidateURLs(ctx context.Context, network Network, urls ...string) ([]Result, error) {
+	return c.Purge(ctx, Invalidate, URL, network, urls...)
+}
+
+// DeleteURLs deletes content by URL. URLs must be absolute, including the scheme and hostname.
+func (c *Client) DeleteURLs(ctx context.Context, network Network, urls ...string) ([]Result, error) {
+	return c.Purge(ctx, Delete, URL, network, urls...)
+}
+
+// InvalidateCPCodes invalidates all content served under the given CP codes.
+func (c *Client) InvalidateCPCodes(ctx context.Context, network Network, cpCodes ...int) ([]Result, error) {
+	return c.Purge(ctx, Invalidate, CPCode, network, formatCPCodes(cpCodes)...)
+}
+
+// DeleteCPCodes deletes all content served under the given CP codes.
+func (c *Client) DeleteCPCodes(ctx context.Context, network Network, cpCodes ...int) ([]Result, error) {
+	return c.Purge(ctx, Delete, CPCode, network, formatCPCodes(cpCodes)...)
+}
+
+// InvalidateTags invalidates all content labelled with the given cache tags.
+func (c *Client) InvalidateTags(ctx context.Context, network Network, tags ...string) ([]Result, error) {
+	return c.Purge(ctx, Invalidate, Tag, network, tags...)
+}
+
+// DeleteTags deletes all content labelled with the given cache tags.
+func (c *Client) DeleteTags(ctx context.Context, network Network, tags ...string) ([]Result, error) {
+	return c.Purge(ctx, Delete, Tag, network, tags...)
+}
+
+// Purge applies action to the objects of the given type on network. CP codes are given in decimal. Objects are sent
+// in as many requests as needed to keep each within MaxRequestSize, and a Result is returned for each request.
+//
+// If a request fails, Purge returns the results of the requests accepted before it along with the error; objects in
+// later requests have not been purged.
+func (c *Client) Purge(ctx context.Context, action Action, objectType ObjectType, network Network, objects ...string) ([]Result, error) {
+	batches, err := batch(objectType, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("%s%s/%s/%s", basePath, action, objectType, network)
+	results := make([]Result, 0, len(batches))
+
+	for _, b := range batches {
+		var resp purgeResp
+
+		err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, path, purgeReq{Objects: b.encoded}, &resp)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, Result{
+			PurgeID:             resp.PurgeID,
+			SupportID:           resp.SupportID,
+			Detail:              resp.Detail,
+			Objects:             b.objects,
+			EstimatedCompletion: time.Now().Add(time.Duration(resp.EstimatedSeconds) * time.Second),
+		})
+	}
+
+	return results, nil
+}
+
+type purgeReq struct {
+	Objects []json.RawMessage `json:"objects"`
+}
+
+type purgeResp struct {
+	HTTPStatus       int    `json:"httpStatus"`
+	Detail           string `json:"detail"`
+	EstimatedSeconds int    `json:"estimatedSeconds"`
+	PurgeID          string `json:"purgeId"`
+	SupportID        string `json:"supportId"`
+}
+
+// objectBatch is a list of objects that fits in one request, along with their JSON encodings.
+type objectBatch struct {
+	objects []string
+	encoded []json.RawMessage
+}
+
+// batch splits objects into batches whose request bodies fit within MaxRequestSize.
+func batch(objectType ObjectType, objects []string) ([]objectBatch, error) {
+	// The body is {"objects":[...]}, with the objects separated by commas.
+	const overhead = len(`{"objects":[]}`)
+
+	var batches []objectBatch
+	var cur objectBatch
+	size := overhead
+
+	for _, o := range objects {
+		enc, err := encodeObject(objectType, o)
+		if err != nil {
+			return nil, err
+		}
+		if overhead+len(enc) > MaxRequestSize {
+			return nil, fmt.Errorf("%s %q is too long to purge", objectType, o)
+		}
+
+		n := len(enc)
+		if len(cur.objects) > 0 {
+			n++
+		}
+		if size+n > MaxRequestSize {
+			batches = append(batches, cur)
+			cur, size, n = objectBatch{}, overhead, len(enc)
+		}
+
+		cur.objects = append(cur.objects, o)
+		cur.encoded = append(cur.encoded, enc)
+		size += n
+	}
+
+	if len(cur.objects) > 0 {
+		batches = append(batches, cur)
+	}
+
+	return batches, nil
+}
+
+// encodeObject returns the JSON encoding of an object: a number for a CP code and a string otherwise.
+func encodeObject(objectType ObjectType, o string) (json.RawMessage, error) {
+	if objectType == CPCode {
+		n, err := strconv.Atoi(o)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CP code %q", o)
+		}
+		return json.Marshal(n)
+	}
+	return json.Marshal(o)
+}
+
+func formatCPCodes(cpCodes []int) []string {
+	s := make([]string, len(cpCodes))
+	for i, code := range cpCodes {
+		s[i] = strconv.Itoa(code)
+	}
+	return s
+}
@@ -0,0 +1,195 @@
+package akamai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Redacted replaces secret values in formatted and logged output.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedFields are the parts of JSON object keys whose values are always redacted from debug output. A key is
+// redacted if it contains any of them, ignoring case, so "secret" covers both "clientSecret" and "client_secret", and
+// "password" covers GTM's "testObjectPassword". Tokens are listed by name because other keys called "token", such as
+// the Edge DNS zone change token, are not secret.
+var DefaultRedactedFields = []string{
+	"access_token",
+	"accessToken",
+	"client_token",
+	"clientToken",
+	"password",
+	"privateKey",
+	"secret",
+}
+
+// String returns a description of the credentials with the client secret redacted and the tokens replaced by their
+// fingerprints.
+func (c Credentials) String() string {
+	return fmt.Sprintf("{ClientSecret:%s AccessToken:%s ClientToken:%s Host:%s}",
+		redactSecret(c.ClientSecret), fingerprintToken(c.AccessToken), fingerprintToken(c.ClientToken), c.Host)
+}
+
+// GoString is like String but in Go syntax, for use with the %#v verb.
+func (c Credentials) GoString() string {
+	return fmt.Sprintf("akamai.Credentials{ClientSecret:%q, AccessToken:%q, ClientToken:%q, Host:%q}",
+		redactSecret(c.ClientSecret), fingerprintToken(c.AccessToken), fingerprintToken(c.ClientToken), c.Host)
+}
+
+// Format implements fmt.Formatter so that every verb, including %+v and %#v, prints redacted credentials.
+func (c Credentials) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, c.GoString())
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", c.String())
+	default:
+		fmt.Fprint(f, c.String())
+	}
+}
+
+// LogValue implements slog.LogValuer so that credentials are redacted in structured logs.
+func (c Credentials) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("client_secret", redactSecret(c.ClientSecret)),
+		slog.String("access_token", fingerprintToken(c.AccessToken)),
+		slog.String("client_token", fingerprintToken(c.ClientToken)),
+		slog.String("host", c.Host),
+	)
+}
+
+// MarshalJSON encodes the credentials with the client secret redacted and the tokens replaced by their fingerprints,
+// so that credentials embedded in other values are not leaked when those values are logged or serialized. The result
+// cannot be decoded back into usable credentials; store credentials with WriteEdgercSection instead.
+func (c Credentials) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ClientSecret string `json:"client_secret"`
+		AccessToken  string `json:"access_token"`
+		ClientToken  string `json:"client_token"`
+		Host         string `json:"host"`
+	}{
+		ClientSecret: redactSecret(c.ClientSecret),
+		AccessToken:  fingerprintToken(c.AccessToken),
+		ClientToken:  fingerprintToken(c.ClientToken),
+		Host:         c.Host,
+	})
+}
+
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+func fingerprintToken(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "sha256:" + Fingerprint(s)
+}
+
+// WithRedactedFields returns a copy of the parent context in which the values of JSON object keys containing any of
+// the given fields are redacted from debug output, in addition to DefaultRedactedFields and any fields set by a parent context.
+func WithRedactedFields(ctx context.Context, fields ...string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	existing, _ := ctx.Value(AkamaiRedactedFields).([]string)
+	all := make([]string, 0, len(existing)+len(fields))
+	all = append(all, existing...)
+	all = append(all, fields...)
+	return context.WithValue(ctx, AkamaiRedactedFields, all)
+}
+
+// RedactedFields returns the parts of JSON object keys whose values are redacted from debug output in the context.
+func RedactedFields(ctx context.Context) []string {
+	fields := append([]string(nil), DefaultRedactedFields...)
+	if ctx != nil {
+		extra, _ := ctx.Value(AkamaiRedactedFields).([]string)
+		fields = append(fields, extra...)
+	}
+	return fields
+}
+
+// RedactJSON returns a copy of body in which the value of every object key that contains one of fields, ignoring case,
+// is replaced by Redacted at any depth. A body that is not valid JSON, or that has no such keys, is returned unchanged,
+// so that numbers, key order and escaping are preserved wherever nothing needs redacting.
+func RedactJSON(body []byte, fields []string) []byte {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return body
+	}
+
+	lower := make([]string, len(fields))
+	for i, f := range fields {
+		lower[i] = strings.ToLower(f)
+	}
+
+	if !redactValue(v, lower) {
+		return body
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// redactValue replaces the values of keys matching fields in v, in place, and reports whether it replaced any.
+func redactValue(v interface{}, fields []string) bool {
+	redacted := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if redactKey(k, fields) {
+				v[k] = Redacted
+				redacted = true
+			} else if redactValue(e, fields) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			if redactValue(e, fields) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+// redactKey reports whether key contains any of fields, which must be lowercase.
+func redactKey(key string, fields []string) bool {
+	key = strings.ToLower(key)
+	for _, f := range fields {
+		if f != "" && strings.Contains(key, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeaders returns a copy of h with the values of the Authorization header replaced by Redacted.
+func RedactHeaders(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range []string{"Authorization", "Proxy-Authorization"} {
+		if _, ok := h[k]; ok {
+			h[k] = []string{Redacted}
+		}
+	}
+	return h
+}
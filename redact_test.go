@@ -0,0 +1,82 @@
+package akamai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "credentials",
+			body: `{"clientSecret":"s","client_secret":"s","clientToken":"t","accessToken":"a","host":"h"}`,
+			want: `{"accessToken":"[REDACTED]","clientSecret":"[REDACTED]","clientToken":"[REDACTED]","client_secret":"[REDACTED]","host":"h"}`,
+		},
+		{
+			name: "GTM liveness test secrets",
+			body: `{"livenessTests":[{"name":"t","testObjectPassword":"p","sslClientPrivateKey":"k","sslClientCertificate":"c"}]}`,
+			want: `{"livenessTests":[{"name":"t","sslClientCertificate":"c","sslClientPrivateKey":"[REDACTED]","testObjectPassword":"[REDACTED]"}]}`,
+		},
+		{
+			name: "case-insensitive",
+			body: `{"PASSWORD":"p","Nested":{"apiSecretKey":"k"}}`,
+			want: `{"Nested":{"apiSecretKey":"[REDACTED]"},"PASSWORD":"[REDACTED]"}`,
+		},
+		{
+			name: "Edge DNS change token",
+			body: `{"zone":"example.com","token":"a1b2c3"}`,
+			want: `{"zone":"example.com","token":"a1b2c3"}`,
+		},
+		{
+			name: "unchanged without matching keys",
+			body: `{"zone": "example.com", "serial": 9007199254740993, "note": "<a&b>"}`,
+			want: `{"zone": "example.com", "serial": 9007199254740993, "note": "<a&b>"}`,
+		},
+		{
+			name: "large numbers and HTML kept when redacting",
+			body: `{"id":9007199254740993,"html":"<a&b>","password":"p"}`,
+			want: `{"html":"<a&b>","id":9007199254740993,"password":"[REDACTED]"}`,
+		},
+		{
+			name: "trailing data",
+			body: `{"password":"p"} {}`,
+			want: `{"password":"p"} {}`,
+		},
+		{
+			name: "not JSON",
+			body: `password=p`,
+			want: `password=p`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(RedactJSON([]byte(tt.body), DefaultRedactedFields)); got != tt.want {
+				t.Errorf("RedactJSON(%s) = %s, want %s", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialsMarshalJSON(t *testing.T) {
+	creds := Credentials{ClientSecret: "secret", AccessToken: "akab-access", ClientToken: "akab-client", Host: "example.com"}
+
+	b, err := json.Marshal(struct {
+		Name        string
+		Credentials Credentials
+	}{"default", creds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"Name":"default","Credentials":{"client_secret":"[REDACTED]",` +
+		`"access_token":"sha256:` + Fingerprint(creds.AccessToken) + `",` +
+		`"client_token":"sha256:` + Fingerprint(creds.ClientToken) + `","host":"example.com"}}`
+	if string(b) != want {
+		t.Errorf("json.Marshal = %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,146 @@
+package akamai
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultReloadInterval is how often a ReloadingEdgercProvider checks its file when it is given an interval of zero.
+const DefaultReloadInterval = 30 * time.Second
+
+// ReloadingEdgercProvider is a CredentialsProvider that watches an .edgerc file and reloads a section of it when the
+// file changes. New credentials are swapped in atomically, so requests signed after a reload use them. If the changed
+// file cannot be read or the section is invalid, the previous credentials are kept and the error is available from
+// LastError until a later reload succeeds.
+//
+// A ReloadingEdgercProvider is safe for concurrent use. Call Close to stop watching the file.
+type ReloadingEdgercProvider struct {
+	path    string
+	section string
+
+	creds   atomic.Pointer[Credentials]
+	lastErr atomic.Pointer[error]
+
+	mu      sync.Mutex // serializes reloads and guards modTime and size
+	modTime time.Time
+	size    int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewReloadingEdgercProvider loads a section of an .edgerc file and starts checking the file for changes every
+// interval, or every DefaultReloadInterval if interval is zero or negative. It returns an error if the initial load
+// fails.
+func NewReloadingEdgercProvider(path string, section string, interval time.Duration) (*ReloadingEdgercProvider, error) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+
+	p := &ReloadingEdgercProvider{
+		path:    path,
+		section: section,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if err := p.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+
+	go p.watch(interval)
+
+	return p, nil
+}
+
+// Retrieve returns the most recently loaded valid credentials.
+func (p *ReloadingEdgercProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	return *p.creds.Load(), nil
+}
+
+// Reload re-reads the file immediately, regardless of whether it has changed. On error the previous credentials are
+// kept.
+func (p *ReloadingEdgercProvider) Reload(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fi, err := os.Stat(p.path)
+	if err != nil {
+		p.lastErr.Store(&err)
+		return err
+	}
+
+	return p.reload(ctx, fi)
+}
+
+// LastError returns the error from the most recent reload, or nil if it succeeded.
+func (p *ReloadingEdgercProvider) LastError() error {
+	if err := p.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Close stops watching the file. Retrieve continues to return the last loaded credentials.
+func (p *ReloadingEdgercProvider) Close() error {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	<-p.done
+	return nil
+}
+
+func (p *ReloadingEdgercProvider) watch(interval time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.reloadIfChanged()
+		}
+	}
+}
+
+func (p *ReloadingEdgercProvider) reloadIfChanged() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fi, err := os.Stat(p.path)
+	if err != nil {
+		p.lastErr.Store(&err)
+		return
+	}
+	if fi.ModTime().Equal(p.modTime) && fi.Size() == p.size {
+		return
+	}
+
+	p.reload(context.Background(), fi)
+}
+
+// reload loads the file, which has the given info. It must be called with mu held.
+func (p *ReloadingEdgercProvider) reload(ctx context.Context, fi os.FileInfo) error {
+	// Record the file info even on failure so that an invalid file is not re-parsed until it changes again.
+	p.modTime = fi.ModTime()
+	p.size = fi.Size()
+
+	c, err := LoadCredentialsFromEdgercFileWithContext(ctx, p.path, p.section)
+	if err != nil {
+		p.lastErr.Store(&err)
+		return err
+	}
+
+	p.creds.Store(&c)
+	p.lastErr.Store(nil)
+
+	return nil
+}
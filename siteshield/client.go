@@ -1,8 +1,12 @@
 package siteshield
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 	"time"
 
 	"net/netip"
@@ -16,13 +20,31 @@ const basePath = "/siteshield/v1/"
 // A Client allows access to the Akamai Site Shield API.
 type Client struct {
 	Credentials akamai.Credentials
+
+	// Provider, if set, supplies the credentials for each request instead of Credentials.
+	Provider akamai.CredentialsProvider
+}
+
+// provider returns the source of credentials for the next request.
+func (c *Client) provider() akamai.CredentialsProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return c.Credentials
 }
 
 // GetMaps returns all maps that belong to the client's account.
+//
+// This is a compatibility wrapper around GetMapsWithContext that uses context.Background() as the context.
 func (c *Client) GetMaps() ([]Map, error) {
+	return c.GetMapsWithContext(context.Background())
+}
+
+// GetMapsWithContext returns all maps that belong to the client's account.
+func (c *Client) GetMapsWithContext(ctx context.Context) ([]Map, error) {
 	var resp mapsResp
 
-	err := request.DoJSON(c.Credentials, http.MethodGet, basePath+"maps", nil, &resp)
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, basePath+"maps", nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +52,7 @@ func (c *Client) GetMaps() ([]Map, error) {
 	maps := make([]Map, len(resp.SiteShieldMaps))
 
 	for i, mapResp := range resp.SiteShieldMaps {
-		m, err := newMapFromResp(mapResp)
+		m, err := newMapFromResp(ctx, mapResp)
 		if err != nil {
 			return nil, err
 		}
@@ -41,15 +63,40 @@ func (c *Client) GetMaps() ([]Map, error) {
 }
 
 // GetMap returns information about a map by ID.
+//
+// This is a compatibility wrapper around GetMapWithContext that uses context.Background() as the context.
 func (c *Client) GetMap(id int) (Map, error) {
+	return c.GetMapWithContext(context.Background(), id)
+}
+
+// GetMapWithContext returns information about a map by ID.
+func (c *Client) GetMapWithContext(ctx context.Context, id int) (Map, error) {
+	var resp mapResp
+
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodGet, fmt.Sprintf("%smaps/%d", basePath, id), nil, &resp)
+	if err != nil {
+		return Map{}, err
+	}
+
+	m, err := newMapFromResp(ctx, resp)
+	if err != nil {
+		return Map{}, err
+	}
+
+	return m, nil
+}
+
+// AcknowledgeMap acknowledges the proposed CIDRs of a map by ID, which allows Akamai to make them current. It returns
+// the map as it stands after acknowledgement, so callers can inspect Acknowledged and AcknowledgedBy.
+func (c *Client) AcknowledgeMap(ctx context.Context, id int) (Map, error) {
 	var resp mapResp
 
-	err := request.DoJSON(c.Credentials, http.MethodGet, fmt.Sprintf("%smaps/%d", basePath, id), nil, &resp)
+	err := request.DoJSONWithContext(ctx, c.provider(), http.MethodPost, fmt.Sprintf("%smaps/%d/acknowledge", basePath, id), nil, &resp)
 	if err != nil {
 		return Map{}, err
 	}
 
-	m, err := newMapFromResp(resp)
+	m, err := newMapFromResp(ctx, resp)
 	if err != nil {
 		return Map{}, err
 	}
@@ -57,8 +104,10 @@ func (c *Client) GetMap(id int) (Map, error) {
 	return m, nil
 }
 
+// Map represents a Site Shield map.
 type Map struct {
-	AcknowledgeRequiredBy time.Time
+	// AcknowledgeRequiredBy is the deadline for acknowledging the proposed CIDRs, or nil if the map has no deadline.
+	AcknowledgeRequiredBy *time.Time
 	Acknowledged          bool
 	AcknowledgedBy        string
 	Alias                 string
@@ -67,16 +116,50 @@ type Map struct {
 	ID                    int
 	IsShared              bool
 	LatestTicketID        int
+	MCMMapRuleID          int
 	ProposedCIDRs         []netip.Prefix
 	RuleName              string
 	Service               Service
-	SureRouteName         string
-	Type                  string
+
+	// ServiceCode is the service code returned by the API, from which Service is derived. It is kept so that codes
+	// unknown to this package are not lost.
+	ServiceCode   string
+	SureRouteName string
+	Type          string
+
+	// Extra holds any fields in the API response that this package does not recognize, keyed by JSON name.
+	Extra map[string]json.RawMessage
+}
+
+// MarshalJSON encodes the map in the format used by the Site Shield API, including any Extra fields.
+func (m Map) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newRespFromMap(m))
 }
 
-func newMapFromResp(r mapResp) (Map, error) {
+// UnmarshalJSON decodes a map in the format used by the Site Shield API. Unrecognized fields are stored in Extra.
+func (m *Map) UnmarshalJSON(b []byte) error {
+	var r mapResp
+	if err := json.Unmarshal(b, &r); err != nil {
+		return err
+	}
+
+	v, err := newMapFromResp(context.Background(), r)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+func newMapFromResp(ctx context.Context, r mapResp) (Map, error) {
 	var m Map
-	m.AcknowledgeRequiredBy = time.UnixMilli(r.AcknowledgeRequiredBy)
+	logger := akamai.Logger(ctx)
+
+	if r.AcknowledgeRequiredBy != 0 {
+		t := time.UnixMilli(r.AcknowledgeRequiredBy)
+		m.AcknowledgeRequiredBy = &t
+	}
 	m.Acknowledged = r.Acknowledged
 	m.AcknowledgedBy = r.AcknowledgedBy
 	m.Alias = r.MapAlias
@@ -88,6 +171,8 @@ func newMapFromResp(r mapResp) (Map, error) {
 	for i, str := range r.CurrentCIDRs {
 		prefix, err := netip.ParsePrefix(str)
 		if err != nil {
+			logger.DebugContext(ctx, "error parsing current CIDR", "cidr", str, "error", err)
+
 			return Map{}, err
 		}
 		m.CurrentCIDRs[i] = prefix
@@ -96,11 +181,14 @@ func newMapFromResp(r mapResp) (Map, error) {
 	m.ID = r.ID
 	m.IsShared = r.Shared
 	m.LatestTicketID = r.LatestTicketID
+	m.MCMMapRuleID = r.MCMMapRuleID
 
 	m.ProposedCIDRs = make([]netip.Prefix, len(r.ProposedCIDRs))
 	for i, str := range r.ProposedCIDRs {
 		prefix, err := netip.ParsePrefix(str)
 		if err != nil {
+			logger.DebugContext(ctx, "error parsing proposed CIDR", "cidr", str, "error", err)
+
 			return Map{}, err
 		}
 		m.ProposedCIDRs[i] = prefix
@@ -108,12 +196,52 @@ func newMapFromResp(r mapResp) (Map, error) {
 
 	m.RuleName = r.RuleName
 	m.Service = parseService(r.Service)
+	m.ServiceCode = r.Service
 	m.SureRouteName = r.SureRouteName
 	m.Type = r.Type
+	m.Extra = r.Extra
 
 	return m, nil
 }
 
+func newRespFromMap(m Map) mapResp {
+	var r mapResp
+
+	if m.AcknowledgeRequiredBy != nil {
+		r.AcknowledgeRequiredBy = m.AcknowledgeRequiredBy.UnixMilli()
+	}
+	r.Acknowledged = m.Acknowledged
+	r.AcknowledgedBy = m.AcknowledgedBy
+	r.Contacts = m.Contacts
+
+	r.CurrentCIDRs = make([]string, len(m.CurrentCIDRs))
+	for i, prefix := range m.CurrentCIDRs {
+		r.CurrentCIDRs[i] = prefix.String()
+	}
+
+	r.ID = m.ID
+	r.LatestTicketID = m.LatestTicketID
+	r.MapAlias = m.Alias
+	r.MCMMapRuleID = m.MCMMapRuleID
+
+	r.ProposedCIDRs = make([]string, len(m.ProposedCIDRs))
+	for i, prefix := range m.ProposedCIDRs {
+		r.ProposedCIDRs[i] = prefix.String()
+	}
+
+	r.RuleName = m.RuleName
+	r.Service = m.ServiceCode
+	if r.Service == "" {
+		r.Service = formatService(m.Service)
+	}
+	r.Shared = m.IsShared
+	r.SureRouteName = m.SureRouteName
+	r.Type = m.Type
+	r.Extra = m.Extra
+
+	return r
+}
+
 type mapsResp struct {
 	SiteShieldMaps []mapResp `json:"siteShieldMaps"`
 }
@@ -134,8 +262,68 @@ type mapResp struct {
 	Shared                bool     `json:"shared"`
 	SureRouteName         string   `json:"sureRouteName"`
 	Type                  string   `json:"type"`
+
+	// Extra holds fields not listed above. It is filled by UnmarshalJSON and merged back in by MarshalJSON.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
+// mapRespFields is the set of JSON field names known to mapResp.
+var mapRespFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(mapResp{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+func (r *mapResp) UnmarshalJSON(b []byte) error {
+	type plain mapResp
+	if err := json.Unmarshal(b, (*plain)(r)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	for name, value := range fields {
+		if mapRespFields[name] {
+			continue
+		}
+		if r.Extra == nil {
+			r.Extra = make(map[string]json.RawMessage)
+		}
+		r.Extra[name] = value
+	}
+
+	return nil
+}
+
+func (r mapResp) MarshalJSON() ([]byte, error) {
+	type plain mapResp
+	b, err := json.Marshal(plain(r))
+	if err != nil || len(r.Extra) == 0 {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range r.Extra {
+		if !mapRespFields[name] {
+			fields[name] = value
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// Service is the kind of traffic served through a Site Shield map.
 type Service string
 
 const (
@@ -157,3 +345,16 @@ func parseService(s string) Service {
 		return ServiceOther
 	}
 }
+
+func formatService(s Service) string {
+	switch s {
+	case ServiceScript:
+		return "C"
+	case ServiceESSL:
+		return "S"
+	case ServiceFreeFlow:
+		return "W"
+	default:
+		return ""
+	}
+}
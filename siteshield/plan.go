@@ -0,0 +1,177 @@
+package siteshield
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"time"
+
+	"github.com/corbaltcode/go-akamai/prefixset"
+)
+
+// A Plan describes the firewall changes needed to move a map from its current CIDRs to its proposed CIDRs.
+//
+// Before acknowledging a map, allow Transition so traffic from both the current and proposed CIDRs is accepted. Once
+// the map is acknowledged and the proposed CIDRs become current, Remove can be dropped from the allowlist.
+type Plan struct {
+	MapID                 int
+	Alias                 string
+	Acknowledged          bool
+	AcknowledgeRequiredBy *time.Time
+
+	// Add contains proposed CIDRs that are not current.
+	Add []netip.Prefix
+
+	// Remove contains current CIDRs that are not proposed. They may be removed after acknowledgement.
+	Remove []netip.Prefix
+
+	// Transition contains the union of the current and proposed CIDRs.
+	Transition []netip.Prefix
+}
+
+// HasChanges reports whether the plan adds or removes any CIDRs.
+func (p Plan) HasChanges() bool {
+	return len(p.Add) > 0 || len(p.Remove) > 0
+}
+
+// PlanMap computes the change plan for a map. A map with no proposed CIDRs has no pending changes.
+//
+// CIDRs are compared as prefixes, so a proposed CIDR that is covered by a broader current CIDR is still reported as
+// an addition. Each list in the plan is sorted and free of duplicates.
+func PlanMap(m Map) Plan {
+	current := normalizePrefixes(m.CurrentCIDRs)
+	proposed := normalizePrefixes(m.ProposedCIDRs)
+
+	p := Plan{
+		MapID:                 m.ID,
+		Alias:                 m.Alias,
+		Acknowledged:          m.Acknowledged,
+		AcknowledgeRequiredBy: m.AcknowledgeRequiredBy,
+	}
+
+	if len(proposed) == 0 {
+		p.Transition = current
+		return p
+	}
+
+	for _, prefix := range proposed {
+		if _, found := slices.BinarySearchFunc(current, prefix, comparePrefixes); !found {
+			p.Add = append(p.Add, prefix)
+		}
+	}
+	for _, prefix := range current {
+		if _, found := slices.BinarySearchFunc(proposed, prefix, comparePrefixes); !found {
+			p.Remove = append(p.Remove, prefix)
+		}
+	}
+	p.Transition = normalizePrefixes(append(slices.Clone(current), proposed...))
+
+	return p
+}
+
+// PlanMaps computes the change plan for each map, in the same order as maps.
+func PlanMaps(maps []Map) []Plan {
+	plans := make([]Plan, len(maps))
+	for i, m := range maps {
+		plans[i] = PlanMap(m)
+	}
+	return plans
+}
+
+// WriteSummary writes a plain-text summary of plans to w, suitable for pasting into a change-management ticket. Maps
+// without changes are listed but their CIDRs are omitted.
+func WriteSummary(w io.Writer, plans []Plan) error {
+	changed := 0
+	for _, p := range plans {
+		if p.HasChanges() {
+			changed++
+		}
+	}
+	if _, err := fmt.Fprintf(w, "Site Shield maps: %d total, %d with proposed changes\n", len(plans), changed); err != nil {
+		return err
+	}
+
+	for _, p := range plans {
+		if _, err := fmt.Fprintf(w, "\nMap %d (%s)\n", p.MapID, p.Alias); err != nil {
+			return err
+		}
+		if !p.HasChanges() {
+			if _, err := fmt.Fprintln(w, "  No changes"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		status := "not acknowledged"
+		if p.Acknowledged {
+			status = "acknowledged"
+		}
+		deadline := "none"
+		if p.AcknowledgeRequiredBy != nil {
+			deadline = p.AcknowledgeRequiredBy.UTC().Format(time.RFC3339)
+		}
+		if _, err := fmt.Fprintf(w, "  Status: %s, acknowledge by: %s\n", status, deadline); err != nil {
+			return err
+		}
+
+		for _, section := range []struct {
+			label    string
+			prefixes []netip.Prefix
+		}{
+			{"Add", p.Add},
+			{"Remove after acknowledgement", p.Remove},
+			{"Allow during transition", p.Transition},
+		} {
+			if _, err := fmt.Fprintf(w, "  %s (%d):\n", section.label, len(section.prefixes)); err != nil {
+				return err
+			}
+			for _, prefix := range section.prefixes {
+				if _, err := fmt.Fprintf(w, "    %s\n", prefix); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// CurrentCIDRSet returns a set containing the current CIDRs of every map.
+func CurrentCIDRSet(maps []Map) *prefixset.Set {
+	s := &prefixset.Set{}
+	for _, m := range maps {
+		for _, p := range m.CurrentCIDRs {
+			s.Add(p)
+		}
+	}
+	return s
+}
+
+// ProposedCIDRSet returns a set containing the proposed CIDRs of every map.
+func ProposedCIDRSet(maps []Map) *prefixset.Set {
+	s := &prefixset.Set{}
+	for _, m := range maps {
+		for _, p := range m.ProposedCIDRs {
+			s.Add(p)
+		}
+	}
+	return s
+}
+
+// normalizePrefixes returns a sorted copy of prefixes with host bits cleared and duplicates removed.
+func normalizePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	out := make([]netip.Prefix, len(prefixes))
+	for i, p := range prefixes {
+		out[i] = p.Masked()
+	}
+	slices.SortFunc(out, comparePrefixes)
+	return slices.Compact(out)
+}
+
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
@@ -0,0 +1,267 @@
+package siteshield
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventType identifies the kind of an Event.
+type EventType string
+
+const (
+	// EventNewProposal is emitted the first time a map is seen with unacknowledged proposed CIDRs.
+	EventNewProposal EventType = "new-proposal"
+
+	// EventDeadlineApproaching is emitted once per proposal when its acknowledgement deadline is within the
+	// watcher's warning window.
+	EventDeadlineApproaching EventType = "deadline-approaching"
+
+	// EventAcknowledged is emitted when a previously unacknowledged proposal is acknowledged.
+	EventAcknowledged EventType = "acknowledged"
+)
+
+// An Event describes a change in the acknowledgement status of a map.
+type Event struct {
+	Type EventType
+	Map  Map
+	Plan Plan
+
+	// Time is when the watcher observed the event.
+	Time time.Time
+}
+
+// WatchState records what a Watcher has already reported, so that each event fires once across runs.
+type WatchState struct {
+	Maps map[int]MapWatchState `json:"maps"`
+}
+
+// MapWatchState records what a Watcher has already reported for a single map.
+type MapWatchState struct {
+	// Proposal identifies the proposal last seen on the map.
+	Proposal string `json:"proposal"`
+
+	Acknowledged bool `json:"acknowledged"`
+	Warned       bool `json:"warned"`
+}
+
+// A StateStore persists WatchState between runs of a Watcher.
+type StateStore interface {
+	Load() (WatchState, error)
+	Save(WatchState) error
+}
+
+// FileStateStore is a StateStore that keeps state as JSON in a file. A missing file is treated as empty state.
+type FileStateStore struct {
+	Path string
+}
+
+// Load reads state from the file.
+func (s FileStateStore) Load() (WatchState, error) {
+	var state WatchState
+
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(b, &state)
+	return state, err
+}
+
+// Save atomically replaces the file with state.
+func (s FileStateStore) Save(state WatchState) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.Path)
+}
+
+// A Watcher polls the Site Shield API for maps with unacknowledged proposals and reports changes through Handler.
+type Watcher struct {
+	Client *Client
+
+	// Handler is called for each event, in the order the events are detected. It may be nil if the caller only uses
+	// the events returned by Check.
+	Handler func(Event)
+
+	// Warning is how long before an acknowledgement deadline an EventDeadlineApproaching is emitted.
+	Warning time.Duration
+
+	// Interval is how often Run polls. If zero, Run polls every 15 minutes.
+	Interval time.Duration
+
+	// State persists what has been reported between runs. If nil, state is kept only in memory.
+	State StateStore
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	state  WatchState
+	loaded bool
+}
+
+// Run calls Check every Interval until the context is done or Check fails.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval == 0 {
+		interval = 15 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check polls the maps once, calls Handler for each new event, saves state and returns the events. State is saved
+// only after every event has been handled, so an event may be reported again if the process stops before the save.
+func (w *Watcher) Check(ctx context.Context) ([]Event, error) {
+	if !w.loaded {
+		if w.State != nil {
+			state, err := w.State.Load()
+			if err != nil {
+				return nil, err
+			}
+			w.state = state
+		}
+		w.loaded = true
+	}
+	if w.state.Maps == nil {
+		w.state.Maps = make(map[int]MapWatchState)
+	}
+
+	maps, err := w.Client.GetMapsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if w.Now != nil {
+		now = w.Now()
+	}
+
+	var events []Event
+	current := make(map[int]bool, len(maps))
+	for _, m := range maps {
+		current[m.ID] = true
+		events = append(events, w.checkMap(m, now)...)
+	}
+
+	// Forget maps that no longer exist, so that state does not grow without bound.
+	for id := range w.state.Maps {
+		if !current[id] {
+			delete(w.state.Maps, id)
+		}
+	}
+
+	// Handle events before saving, so that events interrupted by a crash are reported again on the next run rather
+	// than lost.
+	if w.Handler != nil {
+		for _, e := range events {
+			w.Handler(e)
+		}
+	}
+
+	if w.State != nil {
+		if err := w.State.Save(w.state); err != nil {
+			return events, err
+		}
+	}
+
+	return events, nil
+}
+
+func (w *Watcher) checkMap(m Map, now time.Time) []Event {
+	plan := PlanMap(m)
+	prev, seen := w.state.Maps[m.ID]
+
+	var events []Event
+	event := func(t EventType) {
+		events = append(events, Event{Type: t, Map: m, Plan: plan, Time: now})
+	}
+
+	if !plan.HasChanges() {
+		// The proposal may have been acknowledged and applied between polls.
+		if seen && !prev.Acknowledged && m.Acknowledged {
+			event(EventAcknowledged)
+		}
+		delete(w.state.Maps, m.ID)
+		return events
+	}
+
+	proposal := proposalKey(m, plan)
+	if !seen || prev.Proposal != proposal {
+		prev = MapWatchState{Proposal: proposal, Acknowledged: m.Acknowledged}
+		if !m.Acknowledged {
+			event(EventNewProposal)
+		}
+	}
+
+	if m.Acknowledged {
+		if !prev.Acknowledged {
+			event(EventAcknowledged)
+		}
+		prev.Acknowledged = true
+	} else {
+		prev.Acknowledged = false
+		deadline := m.AcknowledgeRequiredBy
+		if !prev.Warned && deadline != nil && !now.Add(w.Warning).Before(*deadline) {
+			event(EventDeadlineApproaching)
+			prev.Warned = true
+		}
+	}
+
+	w.state.Maps[m.ID] = prev
+	return events
+}
+
+// proposalKey identifies a proposal by its ticket and proposed CIDRs.
+func proposalKey(m Map, plan Plan) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(m.LatestTicketID))
+	for _, p := range plan.Add {
+		b.WriteString(" +")
+		b.WriteString(p.String())
+	}
+	for _, p := range plan.Remove {
+		b.WriteString(" -")
+		b.WriteString(p.String())
+	}
+	return b.String()
+}